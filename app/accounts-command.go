@@ -0,0 +1,86 @@
+package app
+
+import (
+	"expense-tracker/models"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+func (c *commandLine) accountsCommand() {
+	if c.Accounts == nil {
+		log.Fatal("Accounts are not configured")
+	}
+
+	switch flag.Arg(1) {
+	case "", "list":
+		entries, rates := c.convert(c.Store.Find(models.Query{}), baseCurrency())
+		models.PrintAccountBalances(c.Accounts.Accounts().Balances(entries))
+		printRatesUsed(rates)
+	case "add":
+		c.addAccountCommand()
+	default:
+		fmt.Fprintf(os.Stderr, "Usage of accounts:\n")
+		fmt.Fprintf(os.Stderr, "  accounts [list]\n")
+		fmt.Fprintf(os.Stderr, "  accounts add <name> [--opening-balance N]\n")
+		os.Exit(1)
+	}
+}
+
+func (c *commandLine) addAccountCommand() {
+	addCommand := flag.NewFlagSet("accounts add", flag.ExitOnError)
+	openingBalance := amountFlag(addCommand, "opening-balance", "Balance of the account before any entry")
+	args := parseInterspersed(addCommand, os.Args[3:])
+
+	if len(args) != 1 {
+		log.Fatal("Account name is required")
+	}
+
+	if err := c.Accounts.Save(models.Account{Name: args[0], OpeningBalance: *openingBalance}); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (c *commandLine) transferCommand() {
+	transferCommand := flag.NewFlagSet("transfer", flag.ExitOnError)
+	from := transferCommand.String("from", "", "Account the money leaves")
+	to := transferCommand.String("to", "", "Account the money goes to")
+	amount := amountFlag(transferCommand, "amount", "Amount transferred")
+	description := transferCommand.String("description", "", "Description of the transfer")
+	transferCommand.Parse(os.Args[2:])
+
+	if *from == "" || *to == "" || *amount == 0 {
+		log.Fatal("From, to and amount are required")
+	}
+	c.requireAccount(*from)
+	c.requireAccount(*to)
+
+	if *description == "" {
+		*description = fmt.Sprintf("%s to %s", *from, *to)
+	}
+
+	error := c.Store.Add(models.Expense{
+		Description: *description,
+		Amount:      *amount,
+		Kind:        models.KindTransfer,
+		Account:     *from,
+		ToAccount:   *to,
+	})
+
+	if error != nil {
+		log.Fatal(error)
+	}
+}
+
+// requireAccount stops on accounts that were not defined, so that a typo does
+// not silently open a new account. Any name is accepted when accounts are not
+// configured.
+func (c *commandLine) requireAccount(name string) {
+	if c.Accounts == nil || name == "" {
+		return
+	}
+	if _, ok := c.Accounts.Accounts().Find(name); !ok {
+		log.Fatalf("Account %s is not defined", name)
+	}
+}
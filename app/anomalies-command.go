@@ -0,0 +1,62 @@
+package app
+
+import (
+	"expense-tracker/models"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+const defaultAnomalyMonths = 6
+
+func (c *commandLine) anomaliesCommand() {
+	anomaliesCommand := flag.NewFlagSet("anomalies", flag.ExitOnError)
+	months := anomaliesCommand.Int("months", defaultAnomalyMonths, "Number of months of history to compare against")
+	threshold := anomaliesCommand.Float64("threshold", models.DefaultAnomalyThreshold, "Score above which an expense is unusual")
+	category := anomaliesCommand.String("category", "", "Category of the expenses")
+	anomaliesCommand.Parse(os.Args[2:])
+
+	if *months <= 0 {
+		log.Fatal("Months must be positive")
+	}
+
+	expenses, rates := c.convert(c.Store.Find(anomalyWindow(*months)), baseCurrency())
+	anomalies := expenses.Anomalies(*threshold)
+	if *category != "" {
+		filtered := []models.Anomaly{}
+		for _, anomaly := range anomalies {
+			if (models.Query{Category: *category}).Matches(anomaly.Expense) {
+				filtered = append(filtered, anomaly)
+			}
+		}
+		anomalies = filtered
+	}
+
+	models.PrintAnomalies(anomalies)
+	printRatesUsed(rates)
+}
+
+// warnAnomaly warns before an expense much larger than usual is added, so that
+// typos such as an extra zero are noticed. The expenses are converted to the
+// base currency when there are rates for all of them, and otherwise compared
+// with those of the same currency only, rather than stopping the add.
+func (c *commandLine) warnAnomaly(expense *models.Expense) {
+	candidate := *expense
+	candidate.CreatedAt = time.Now()
+	history := append(c.Store.Find(anomalyWindow(defaultAnomalyMonths)), &candidate)
+	if c.Rates != nil {
+		if converted, _, err := c.Rates.Rates().Convert(history, baseCurrency(), baseCurrency()); err == nil {
+			history = converted
+		}
+	}
+
+	if anomaly, ok := history.Check(history[len(history)-1], models.DefaultAnomalyThreshold); ok {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", anomaly)
+	}
+}
+
+func anomalyWindow(months int) models.Query {
+	return models.Query{Kind: models.KindExpense, From: time.Now().AddDate(0, -months, 0)}
+}
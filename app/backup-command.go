@@ -0,0 +1,67 @@
+package app
+
+import (
+	"expense-tracker/models"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+)
+
+func (c *commandLine) backupCommand() {
+	backuper, ok := models.As[models.Backuper](c.Store)
+	if !ok {
+		log.Fatal("Backups are not supported by the store")
+	}
+
+	switch flag.Arg(1) {
+	case "list":
+		backups, err := backuper.Backups()
+		if err != nil {
+			log.Fatal(err)
+		}
+		models.PrintBackups(backups)
+	case "create":
+		backup, err := backuper.CreateBackup()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Created backup %s\n", backup.Id)
+	case "restore":
+		c.restoreBackupCommand(backuper)
+	default:
+		fmt.Fprintf(os.Stderr, "Usage of backup:\n")
+		fmt.Fprintf(os.Stderr, "  backup list\n")
+		fmt.Fprintf(os.Stderr, "  backup create\n")
+		fmt.Fprintf(os.Stderr, "  backup restore <id> [--yes]\n")
+		os.Exit(1)
+	}
+}
+
+// restoreBackupCommand previews what restoring the backup changes before
+// asking to restore it.
+func (c *commandLine) restoreBackupCommand(backuper models.Backuper) {
+	restoreCommand := flag.NewFlagSet("backup restore", flag.ExitOnError)
+	yes := restoreCommand.Bool("yes", false, "Restore without asking")
+	args := parseInterspersed(restoreCommand, os.Args[3:])
+
+	if len(args) != 1 {
+		log.Fatal("Backup ID is required")
+	}
+	id := args[0]
+
+	backup, err := backuper.BackupExpenses(id)
+	if err != nil {
+		log.Fatal(err)
+	}
+	current := slices.Concat(c.Store.Find(models.Query{}), c.Store.Trashed())
+	models.PrintExpenseDiffs(models.DiffExpenses(current, backup))
+
+	if !*yes && !confirm("Restore this backup?") {
+		log.Fatal("Backup not restored")
+	}
+	if err := backuper.RestoreBackup(id); err != nil {
+		log.Fatal(err)
+	}
+}
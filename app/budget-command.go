@@ -0,0 +1,92 @@
+package app
+
+import (
+	"expense-tracker/models"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+func (c *commandLine) budgetCommand() {
+	if c.Budgets == nil {
+		log.Fatal("Budgets are not configured")
+	}
+
+	switch flag.Arg(1) {
+	case "set":
+		c.setBudgetCommand()
+	case "status":
+		c.budgetStatusCommand()
+	default:
+		fmt.Fprintf(os.Stderr, "Usage of budget:\n")
+		fmt.Fprintf(os.Stderr, "  budget set --category <category> --amount N [--month N] [--rollover]\n")
+		fmt.Fprintf(os.Stderr, "  budget status [--month N]\n")
+		os.Exit(1)
+	}
+}
+
+func (c *commandLine) setBudgetCommand() {
+	setCommand := flag.NewFlagSet("budget set", flag.ExitOnError)
+	category := setCommand.String("category", "", "Category of the budget")
+	amount := amountFlag(setCommand, "amount", "Monthly amount of the budget")
+	month := setCommand.Int("month", int(time.Now().Month()), "Month of the current year the budget starts")
+	rollover := setCommand.Bool("rollover", false, "Carry what is left unspent to the next month")
+	setCommand.Parse(os.Args[3:])
+
+	if *category == "" || *amount == 0 {
+		log.Fatal("Category and amount are required")
+	}
+	if *month < 1 || *month > 12 {
+		log.Fatal("Month must be between 1 and 12")
+	}
+
+	budget := models.Budget{
+		Category: *category,
+		Amount:   *amount,
+		Rollover: *rollover,
+		From:     models.MonthStart(time.Now().Year(), time.Month(*month)),
+	}
+	if err := c.Budgets.Set(budget); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (c *commandLine) budgetStatusCommand() {
+	statusCommand := flag.NewFlagSet("budget status", flag.ExitOnError)
+	month := statusCommand.Int("month", int(time.Now().Month()), "Month of the current year")
+	statusCommand.Parse(os.Args[3:])
+
+	if *month < 1 || *month > 12 {
+		log.Fatal("Month must be between 1 and 12")
+	}
+
+	// budgets are set in the base currency
+	expenses, rates := c.convert(c.Store.Find(models.Query{}), baseCurrency())
+	statuses := c.Budgets.Budgets().Status(expenses, models.MonthStart(time.Now().Year(), time.Month(*month)))
+	models.PrintBudgetStatuses(statuses, colored())
+	printRatesUsed(rates)
+}
+
+func (c *commandLine) forecastCommand() {
+	forecastCommand := flag.NewFlagSet("forecast", flag.ExitOnError)
+	forecastCommand.Parse(os.Args[2:])
+
+	now := time.Now()
+	expenses, rates := c.convert(c.Store.Find(models.Query{}), baseCurrency())
+
+	budgets := []models.BudgetStatus{}
+	if c.Budgets != nil {
+		budgets = c.Budgets.Budgets().Status(expenses, models.MonthStart(now.Year(), now.Month()))
+	}
+
+	expenses.Forecast(now, budgets).Print()
+	printRatesUsed(rates)
+}
+
+// colored follows the NO_COLOR convention to turn off colored output.
+func colored() bool {
+	_, disabled := os.LookupEnv("NO_COLOR")
+	return !disabled
+}
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"time"
+
+	"golang.org/x/term"
 )
 
 type (
@@ -15,12 +18,53 @@ type (
 	}
 
 	commandLine struct {
-		Store models.Store
+		Store    models.Store
+		Rates    models.RateStore
+		Accounts models.AccountStore
+		Budgets  models.BudgetStore
+		Audit    models.AuditStore
+		Exporter models.Exporter
 	}
+
+	Option func(*commandLine)
 )
 
-func NewCommandLine(store models.Store) CommandLine {
-	return &commandLine{store}
+func NewCommandLine(store models.Store, options ...Option) CommandLine {
+	commandLine := &commandLine{Store: store}
+	for _, option := range options {
+		option(commandLine)
+	}
+	return commandLine
+}
+
+func WithRates(rates models.RateStore) Option {
+	return func(c *commandLine) {
+		c.Rates = rates
+	}
+}
+
+func WithAccounts(accounts models.AccountStore) Option {
+	return func(c *commandLine) {
+		c.Accounts = accounts
+	}
+}
+
+func WithBudgets(budgets models.BudgetStore) Option {
+	return func(c *commandLine) {
+		c.Budgets = budgets
+	}
+}
+
+func WithAudit(audit models.AuditStore) Option {
+	return func(c *commandLine) {
+		c.Audit = audit
+	}
+}
+
+func WithExporter(exporter models.Exporter) Option {
+	return func(c *commandLine) {
+		c.Exporter = exporter
+	}
 }
 
 func (c *commandLine) Run() {
@@ -29,10 +73,34 @@ func (c *commandLine) Run() {
 		fmt.Fprintf(os.Stderr, "  %s <command> [arguments]\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "\n")
 		fmt.Fprintf(os.Stderr, "Commands:\n")
-		fmt.Fprintf(os.Stderr, "  add       Add an expense\n")
-		fmt.Fprintf(os.Stderr, "  list      List expenses\n")
-		fmt.Fprintf(os.Stderr, "  delete    Delete expenses\n")
-		fmt.Fprintf(os.Stderr, "  summary   Summary expenses\n")
+		fmt.Fprintf(os.Stderr, "  add        Add an expense\n")
+		fmt.Fprintf(os.Stderr, "  add-income Add an income\n")
+		fmt.Fprintf(os.Stderr, "  list       List expenses\n")
+		fmt.Fprintf(os.Stderr, "  delete     Delete expenses\n")
+		fmt.Fprintf(os.Stderr, "  restore    Restore a deleted expense\n")
+		fmt.Fprintf(os.Stderr, "  trash      List or empty deleted expenses\n")
+		fmt.Fprintf(os.Stderr, "  undo       Undo the last changes\n")
+		fmt.Fprintf(os.Stderr, "  redo       Redo the last undone changes\n")
+		fmt.Fprintf(os.Stderr, "  history    List the recent changes\n")
+		fmt.Fprintf(os.Stderr, "  expense    Show the change history of an expense\n")
+		fmt.Fprintf(os.Stderr, "  compact    Compact the event log into a snapshot\n")
+		fmt.Fprintf(os.Stderr, "  verify     Verify the ledger was not altered\n")
+		fmt.Fprintf(os.Stderr, "  encrypt    Encrypt the ledger files\n")
+		fmt.Fprintf(os.Stderr, "  decrypt    Decrypt the ledger files\n")
+		fmt.Fprintf(os.Stderr, "  backup     List, create or restore backups of the ledger\n")
+		fmt.Fprintf(os.Stderr, "  export     Export expenses to a CSV or JSON file\n")
+		fmt.Fprintf(os.Stderr, "  refund     Refund an expense\n")
+		fmt.Fprintf(os.Stderr, "  summary    Summary expenses\n")
+		fmt.Fprintf(os.Stderr, "  stats      Statistics of expenses\n")
+		fmt.Fprintf(os.Stderr, "  chart      Chart expenses\n")
+		fmt.Fprintf(os.Stderr, "  rates      Manage currency rates\n")
+		fmt.Fprintf(os.Stderr, "  accounts   Manage accounts and show their balances\n")
+		fmt.Fprintf(os.Stderr, "  transfer   Transfer between accounts\n")
+		fmt.Fprintf(os.Stderr, "  settle     Settle up split expenses\n")
+		fmt.Fprintf(os.Stderr, "  budget     Manage category budgets\n")
+		fmt.Fprintf(os.Stderr, "  forecast   Forecast the spending of the month\n")
+		fmt.Fprintf(os.Stderr, "  anomalies  List unusually large expenses\n")
+		fmt.Fprintf(os.Stderr, "  dedupe     Find, merge or delete duplicate expenses\n")
 		fmt.Fprintf(os.Stderr, "\n")
 		fmt.Fprintf(os.Stderr, "Flags:\n")
 		flag.PrintDefaults()
@@ -41,13 +109,61 @@ func (c *commandLine) Run() {
 	flag.Parse()
 	switch flag.Arg(0) {
 	case "add":
-		c.addExpenseCommand()
+		c.addExpenseCommand(models.KindExpense)
+	case "add-income":
+		c.addExpenseCommand(models.KindIncome)
 	case "list":
 		c.listExpensesCommand()
 	case "delete":
 		c.deleteExpensesCommand()
+	case "restore":
+		c.restoreCommand()
+	case "trash":
+		c.trashCommand()
+	case "undo":
+		c.undoCommand()
+	case "redo":
+		c.redoCommand()
+	case "history":
+		c.historyCommand()
+	case "expense":
+		c.expenseCommand()
+	case "compact":
+		c.compactCommand()
+	case "verify":
+		c.verifyCommand()
+	case "encrypt":
+		c.encryptCommand()
+	case "decrypt":
+		c.decryptCommand()
+	case "backup":
+		c.backupCommand()
+	case "export":
+		c.exportCommand()
+	case "refund":
+		c.refundExpenseCommand()
 	case "summary":
 		c.summaryExpensesCommand()
+	case "stats":
+		c.statsExpensesCommand()
+	case "chart":
+		c.chartExpensesCommand()
+	case "rates":
+		c.ratesCommand()
+	case "accounts":
+		c.accountsCommand()
+	case "transfer":
+		c.transferCommand()
+	case "settle":
+		c.settleCommand()
+	case "budget":
+		c.budgetCommand()
+	case "forecast":
+		c.forecastCommand()
+	case "anomalies":
+		c.anomaliesCommand()
+	case "dedupe":
+		c.dedupeCommand()
 	default:
 		flag.Usage()
 		os.Exit(1)
@@ -55,17 +171,70 @@ func (c *commandLine) Run() {
 
 }
 
-func (c *commandLine) addExpenseCommand() {
-	addCommand := flag.NewFlagSet("add", flag.ExitOnError)
+func (c *commandLine) addExpenseCommand(defaultKind models.Kind) {
+	addCommand := flag.NewFlagSet(flag.Arg(0), flag.ExitOnError)
 	description := addCommand.String("description", "", "Description of the expense")
-	amount := addCommand.Int("amount", 0, "Amount of the expense")
+	amount := amountFlag(addCommand, "amount", "Amount of the expense, e.g. 12.50")
+	category := addCommand.String("category", "", "Category of the expense")
+	currency := addCommand.String("currency", "", "Currency of the expense, defaults to the base currency")
+	kind := addCommand.String("kind", string(defaultKind), "Kind of the entry: expense or income")
+	account := addCommand.String("account", "", "Account or payment method of the entry")
+	split := addCommand.String("split", "", "People sharing the expense: a,b equally, a:60%,b:40% or a:12,b:8")
+	paidBy := addCommand.String("paid-by", "", "Person who paid a split expense")
+	recurring := addCommand.Bool("recurring", false, "Expense repeating every month")
+	anomalyCheck := addCommand.Bool("anomaly-check", true, "Warn when the amount is unusual for the category")
+	force := addCommand.Bool("force", false, "Add without asking when it looks like a duplicate")
 	addCommand.Parse(os.Args[2:])
 
 	if *description == "" || *amount == 0 {
 		log.Fatal("Description and amount are required")
 	}
 
-	error := c.Store.Add(models.Expense{Description: *description, Amount: *amount})
+	if *currency != "" {
+		code, err := models.ParseCurrency(*currency)
+		if err != nil {
+			log.Fatal(err)
+		}
+		*currency = code
+	}
+
+	entryKind, err := models.ParseKind(*kind)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if entryKind != models.KindExpense && entryKind != models.KindIncome {
+		log.Fatal("Use transfer and refund to record transfers and refunds")
+	}
+	c.requireAccount(*account)
+
+	var shares models.Shares
+	if *split != "" {
+		shares, err = models.ParseSplit(*split, *amount)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	expense := models.Expense{
+		Description: *description,
+		Amount:      *amount,
+		Category:    *category,
+		Currency:    *currency,
+		Kind:        entryKind,
+		Account:     *account,
+		PaidBy:      *paidBy,
+		Split:       shares,
+		Recurring:   *recurring,
+	}
+
+	if *anomalyCheck && entryKind == models.KindExpense {
+		c.warnAnomaly(&expense)
+	}
+	if !*force {
+		c.confirmNotDuplicate(expense)
+	}
+
+	error := c.Store.Add(expense)
 
 	if error != nil {
 		log.Fatal(error)
@@ -74,9 +243,37 @@ func (c *commandLine) addExpenseCommand() {
 
 func (c *commandLine) listExpensesCommand() {
 	listCommand := flag.NewFlagSet("list", flag.ExitOnError)
+	sortBy := listCommand.String("sort", "id", "Sort by id, amount, date or description")
+	desc := listCommand.Bool("desc", false, "Sort in descending order")
+	limit := listCommand.Int("limit", 0, "Maximum number of expenses to list")
+	offset := listCommand.Int("offset", 0, "Number of expenses to skip")
+	tail := listCommand.Int("tail", 0, "List only the last N expenses")
+	category := listCommand.String("category", "", "Category of the expenses")
+	account := listCommand.String("account", "", "Account of the expenses")
+	from := listCommand.String("from", "", "List expenses from this date (YYYY-MM-DD)")
+	to := listCommand.String("to", "", "List expenses up to this date (YYYY-MM-DD)")
 	listCommand.Parse(os.Args[2:])
 
-	c.Store.List()
+	sortField, err := models.ParseSortField(*sortBy)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	query, err := rangeQuery(0, *from, *to, *category)
+	if err != nil {
+		log.Fatal(err)
+	}
+	query.SortBy = sortField
+	query.Desc = *desc
+	query.Limit = *limit
+	query.Offset = *offset
+	query.Tail = *tail
+	query.Account = *account
+	if err := query.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	c.Store.ListBy(query)
 }
 
 func (c *commandLine) deleteExpensesCommand() {
@@ -95,13 +292,233 @@ func (c *commandLine) deleteExpensesCommand() {
 	}
 }
 
+func (c *commandLine) refundExpenseCommand() {
+	refundCommand := flag.NewFlagSet("refund", flag.ExitOnError)
+	refundId := refundCommand.Int("id", 0, "ID of the refunded expense")
+	amount := amountFlag(refundCommand, "amount", "Amount refunded, defaults to what is left of the expense")
+	description := refundCommand.String("description", "", "Description of the refund")
+	refundCommand.Parse(os.Args[2:])
+
+	if *refundId == 0 {
+		log.Fatal("ID is required")
+	}
+
+	original, err := c.Store.Get(*refundId)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *amount == 0 {
+		*amount = original.Net(c.Store.Find(models.Query{Kind: models.KindRefund}).Refunded())
+	}
+	if *description == "" {
+		*description = fmt.Sprintf("Refund of #%d", original.Id)
+	}
+
+	error := c.Store.Add(models.Expense{
+		Description: *description,
+		Amount:      *amount,
+		Category:    original.Category,
+		Currency:    original.Currency,
+		Kind:        models.KindRefund,
+		RefundOf:    original.Id,
+		Account:     original.Account,
+	})
+
+	if error != nil {
+		log.Fatal(error)
+	}
+}
+
+func (c *commandLine) settleCommand() {
+	settleCommand := flag.NewFlagSet("settle", flag.ExitOnError)
+	settleCommand.Parse(os.Args[2:])
+
+	expenses, rates := c.convert(c.Store.Find(models.Query{Kind: models.KindExpense}), baseCurrency())
+	balances := expenses.PersonBalances()
+	fmt.Printf("Balances:\n")
+	for _, balance := range balances {
+		balance.Print()
+	}
+
+	settlements := models.Settle(balances)
+	if len(settlements) == 0 {
+		fmt.Printf("\nEveryone is settled up\n")
+	} else {
+		fmt.Printf("\nTransfers:\n")
+		for _, settlement := range settlements {
+			settlement.Print()
+		}
+	}
+	printRatesUsed(rates)
+}
+
 func (c *commandLine) summaryExpensesCommand() {
 	summaryCommand := flag.NewFlagSet("summary", flag.ExitOnError)
 	summaryMonth := summaryCommand.Int("month", 0, "Month of the summary")
+	summaryGroupBy := summaryCommand.String("group-by", "", "Group the summary by month, week, year or weekday")
+	summaryBase := summaryCommand.String("base", baseCurrency(), "Currency the summary is converted to")
 	summaryCommand.Parse(os.Args[2:])
-	if *summaryMonth == 0 {
+
+	var groupBy models.GroupBy
+	if *summaryGroupBy != "" {
+		if *summaryMonth != 0 {
+			log.Fatal("Month and group-by cannot be used together")
+		}
+		parsed, err := models.ParseGroupBy(*summaryGroupBy)
+		if err != nil {
+			log.Fatal(err)
+		}
+		groupBy = parsed
+	}
+
+	base, err := models.ParseCurrency(*summaryBase)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	query, err := rangeQuery(*summaryMonth, "", "", "")
+	if err != nil {
+		log.Fatal(err)
+	}
+	switch {
+	case c.hasForeignCurrency(query, base):
+		c.convertedSummary(c.Store.Find(query), base, groupBy)
+	case groupBy != "":
+		c.Store.SummaryGroupedBy(groupBy)
+	case *summaryMonth == 0:
 		c.Store.Summary()
-		os.Exit(0)
+	default:
+		c.Store.SummaryForMonth(time.Month(*summaryMonth))
+	}
+}
+
+func (c *commandLine) statsExpensesCommand() {
+	statsCommand := flag.NewFlagSet("stats", flag.ExitOnError)
+	month := statsCommand.Int("month", 0, "Month of the current year")
+	from := statsCommand.String("from", "", "Statistics from this date (YYYY-MM-DD)")
+	to := statsCommand.String("to", "", "Statistics up to this date (YYYY-MM-DD)")
+	category := statsCommand.String("category", "", "Category of the expenses")
+	top := statsCommand.Int("top", 5, "Number of largest expenses to show")
+	base := statsCommand.String("base", baseCurrency(), "Currency the statistics are converted to")
+	statsCommand.Parse(os.Args[2:])
+
+	query, err := rangeQuery(*month, *from, *to, *category)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := query.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	code, err := models.ParseCurrency(*base)
+	if err != nil {
+		log.Fatal(err)
 	}
-	c.Store.SummaryForMonth(time.Month(*summaryMonth))
+
+	query.Kind = models.KindExpense
+	expenses, rates := c.convert(c.Store.Find(query), code)
+	models.PrintStats(expenses, *top)
+	printRatesUsed(rates)
+}
+
+func (c *commandLine) chartExpensesCommand() {
+	chartCommand := flag.NewFlagSet("chart", flag.ExitOnError)
+	groupBy := chartCommand.String("group-by", "", "Chart totals by month, week, year or weekday")
+	by := chartCommand.String("by", "", "Chart totals by category")
+	month := chartCommand.Int("month", 0, "Month of the current year")
+	from := chartCommand.String("from", "", "Chart from this date (YYYY-MM-DD)")
+	to := chartCommand.String("to", "", "Chart up to this date (YYYY-MM-DD)")
+	category := chartCommand.String("category", "", "Category of the expenses")
+	chartCommand.Parse(os.Args[2:])
+
+	query, err := rangeQuery(*month, *from, *to, *category)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := query.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	// the same spending as the summary: refunds are deducted on their own
+	// dates and foreign currencies are converted
+	expenses, _ := c.convert(c.Store.Find(query), baseCurrency())
+	width := terminalWidth()
+
+	switch {
+	case *groupBy != "" && *by != "":
+		log.Fatal("Group-by and by cannot be used together")
+	case *by == "category":
+		fmt.Print(models.RenderBarChart(expenses.CategoryBars(), width))
+	case *by != "":
+		log.Fatal("Only charts by category are supported")
+	default:
+		if *groupBy == "" {
+			*groupBy = string(models.GroupByMonth)
+		}
+		period, err := models.ParseGroupBy(*groupBy)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Print(models.RenderBarChart(models.PeriodBars(expenses.GroupBy(period)), width))
+	}
+
+	fmt.Printf("\nDaily spending: %s\n", models.Sparkline(expenses.DailyTotals(), width-len("Daily spending: ")))
+}
+
+// terminalWidth is the width of the terminal the output is shown on, unless
+// overridden by exporting COLUMNS, which shells set but do not export. Output
+// that is not a terminal gets the default width.
+func terminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	if columns, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && columns > 0 {
+		return columns
+	}
+	return models.DefaultChartWidth
+}
+
+// amountFlag defines a flag for an amount of money, with up to two decimals.
+func amountFlag(flags *flag.FlagSet, name string, usage string) *int {
+	amount := new(int)
+	flags.Func(name, usage, func(value string) error {
+		parsed, err := models.ParseAmount(value)
+		*amount = parsed
+		return err
+	})
+	return amount
+}
+
+// rangeQuery builds a query from the month, from, to and category flags shared
+// by the reporting commands. The to date is inclusive.
+func rangeQuery(month int, from string, to string, category string) (models.Query, error) {
+	query := models.Query{Category: category}
+
+	if month != 0 {
+		if from != "" || to != "" {
+			return query, fmt.Errorf("month cannot be used together with from or to")
+		}
+		if month < 1 || month > 12 {
+			return query, fmt.Errorf("month must be between 1 and 12")
+		}
+		return query.ForMonth(time.Month(month), time.Now().Year()), nil
+	}
+
+	if from != "" {
+		date, err := time.Parse(models.DateFormat, from)
+		if err != nil {
+			return query, err
+		}
+		query.From = date
+	}
+	if to != "" {
+		date, err := time.Parse(models.DateFormat, to)
+		if err != nil {
+			return query, err
+		}
+		query.To = date.AddDate(0, 0, 1)
+	}
+
+	return query, nil
 }
@@ -0,0 +1,21 @@
+package app
+
+import (
+	"expense-tracker/models"
+	"flag"
+	"log"
+	"os"
+)
+
+func (c *commandLine) compactCommand() {
+	compactCommand := flag.NewFlagSet("compact", flag.ExitOnError)
+	compactCommand.Parse(os.Args[2:])
+
+	compactor, ok := models.As[models.Compactor](c.Store)
+	if !ok {
+		log.Fatal("The store cannot be compacted")
+	}
+	if err := compactor.Compact(); err != nil {
+		log.Fatal(err)
+	}
+}
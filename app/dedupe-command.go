@@ -0,0 +1,116 @@
+package app
+
+import (
+	"bufio"
+	"expense-tracker/models"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (c *commandLine) dedupeCommand() {
+	dedupeCommand := flag.NewFlagSet("dedupe", flag.ExitOnError)
+	days := dedupeCommand.Int("days", models.DefaultDuplicateDays, "Days apart duplicates can be")
+	merge := dedupeCommand.String("merge", "", "IDs to merge into the first one, e.g. 3,7")
+	remove := dedupeCommand.String("delete", "", "IDs of the duplicates to delete, e.g. 7,9")
+	dedupeCommand.Parse(os.Args[2:])
+
+	switch {
+	case *merge != "" && *remove != "":
+		log.Fatal("Merge and delete cannot be used together")
+	case *merge != "":
+		c.mergeDuplicates(parseIds(*merge))
+	case *remove != "":
+		for _, id := range parseIds(*remove) {
+			if err := c.Store.Delete(id); err != nil {
+				log.Fatal(err)
+			}
+		}
+	default:
+		groups := c.Store.Find(models.Query{}).DuplicateGroups(*days)
+		if len(groups) == 0 {
+			fmt.Printf("No suspected duplicates\n")
+			return
+		}
+		for i, group := range groups {
+			if i > 0 {
+				fmt.Printf("\n")
+			}
+			group.Print()
+		}
+	}
+}
+
+func (c *commandLine) mergeDuplicates(ids []int) {
+	if err := models.ValidateMerge(ids); err != nil {
+		log.Fatal(err)
+	}
+
+	kept, err := c.Store.Get(ids[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	duplicates := models.Expenses{}
+	for _, id := range ids[1:] {
+		duplicate, err := c.Store.Get(id)
+		if err != nil {
+			log.Fatal(err)
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+
+	kept.Merge(duplicates)
+	if err := c.Store.Update(*kept); err != nil {
+		log.Fatal(err)
+	}
+	for _, duplicate := range duplicates {
+		if err := c.Store.Delete(duplicate.Id); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// confirmNotDuplicate asks before adding an entry that looks like one already
+// recorded, stopping unless the answer is yes.
+func (c *commandLine) confirmNotDuplicate(expense models.Expense) {
+	expense.CreatedAt = time.Now()
+	// only the last days can hold a duplicate, a day more covers the start
+	// of the first one, so stores by date read no further back
+	query := models.Query{Kind: expense.TransactionKind(), From: expense.CreatedAt.AddDate(0, 0, -models.DefaultDuplicateDays-1)}
+	duplicates := c.Store.Find(query).DuplicatesOf(&expense, models.DefaultDuplicateDays)
+	if len(duplicates) == 0 {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Possible duplicate of:\n")
+	for _, duplicate := range duplicates {
+		fmt.Fprintf(os.Stderr, "  #%d %s %s (%s)\n", duplicate.Id, duplicate.Description, models.FormatAmount(duplicate.Amount), duplicate.CreatedAt.Format(models.DateFormat))
+	}
+	if !confirm("Add it anyway?") {
+		log.Fatal("Expense not added")
+	}
+}
+
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func parseIds(value string) []int {
+	ids := []int{}
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			log.Fatalf("Invalid ID %q", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"expense-tracker/models"
+	"flag"
+	"log"
+	"os"
+)
+
+func (c *commandLine) encryptCommand() {
+	encryptCommand := flag.NewFlagSet("encrypt", flag.ExitOnError)
+	encryptCommand.Parse(os.Args[2:])
+
+	for _, encrypter := range c.encrypters() {
+		if err := encrypter.Encrypt(); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func (c *commandLine) decryptCommand() {
+	decryptCommand := flag.NewFlagSet("decrypt", flag.ExitOnError)
+	decryptCommand.Parse(os.Args[2:])
+
+	for _, encrypter := range c.encrypters() {
+		if err := encrypter.Decrypt(); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// encrypters are the stores of the chain, the audit trail, the rates, the
+// accounts and the budgets whose files can be encrypted.
+func (c *commandLine) encrypters() []models.Encrypter {
+	encrypters := []models.Encrypter{}
+	for _, store := range models.Chain(c.Store) {
+		if encrypter, ok := store.(models.Encrypter); ok {
+			encrypters = append(encrypters, encrypter)
+		}
+	}
+	for _, store := range []any{c.Audit, c.Rates, c.Accounts, c.Budgets} {
+		if encrypter, ok := store.(models.Encrypter); ok {
+			encrypters = append(encrypters, encrypter)
+		}
+	}
+	if len(encrypters) == 0 {
+		log.Fatal("The store cannot be encrypted")
+	}
+	return encrypters
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+func (c *commandLine) expenseCommand() {
+	switch flag.Arg(1) {
+	case "history":
+		c.expenseHistoryCommand()
+	default:
+		fmt.Fprintf(os.Stderr, "Usage of expense:\n")
+		fmt.Fprintf(os.Stderr, "  expense history --id N\n")
+		os.Exit(1)
+	}
+}
+
+func (c *commandLine) expenseHistoryCommand() {
+	if c.Audit == nil {
+		log.Fatal("Audit trail is not configured")
+	}
+
+	historyCommand := flag.NewFlagSet("expense history", flag.ExitOnError)
+	id := historyCommand.Int("id", 0, "ID of the expense")
+	historyCommand.Parse(os.Args[3:])
+
+	if *id == 0 {
+		log.Fatal("ID is required")
+	}
+
+	revisions := c.Audit.Revisions(*id)
+	if len(revisions) == 0 {
+		log.Fatalf("No history for expense with ID %d", *id)
+	}
+	revisions.Print()
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+func (c *commandLine) exportCommand() {
+	if c.Exporter == nil {
+		log.Fatal("Export is not configured")
+	}
+
+	exportCommand := flag.NewFlagSet("export", flag.ExitOnError)
+	output := exportCommand.String("output", "", "File to export to: .csv or .json, compressed when ending with .gz")
+	category := exportCommand.String("category", "", "Category of the expenses")
+	from := exportCommand.String("from", "", "Export expenses from this date (YYYY-MM-DD)")
+	to := exportCommand.String("to", "", "Export expenses up to this date (YYYY-MM-DD)")
+	exportCommand.Parse(os.Args[2:])
+
+	if *output == "" {
+		log.Fatal("Output is required")
+	}
+
+	query, err := rangeQuery(0, *from, *to, *category)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	expenses := c.Store.Find(query)
+	if err := c.Exporter.Export(*output, expenses); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Exported %d expenses to %s\n", len(expenses), *output)
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"expense-tracker/models"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+func (c *commandLine) undoCommand() {
+	undoCommand := flag.NewFlagSet("undo", flag.ExitOnError)
+	n := undoCommand.Int("n", 1, "Number of changes to undo")
+	undoCommand.Parse(os.Args[2:])
+
+	undone, err := c.journal().Undo(*n)
+	for _, operation := range undone {
+		fmt.Printf("Undid %s\n", operation)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (c *commandLine) redoCommand() {
+	redoCommand := flag.NewFlagSet("redo", flag.ExitOnError)
+	n := redoCommand.Int("n", 1, "Number of undone changes to redo")
+	redoCommand.Parse(os.Args[2:])
+
+	redone, err := c.journal().Redo(*n)
+	for _, operation := range redone {
+		fmt.Printf("Redid %s\n", operation)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (c *commandLine) historyCommand() {
+	historyCommand := flag.NewFlagSet("history", flag.ExitOnError)
+	limit := historyCommand.Int("limit", 10, "Number of recent changes to list")
+	historyCommand.Parse(os.Args[2:])
+
+	operations := c.journal().History()
+	if *limit > 0 && len(operations) > *limit {
+		operations = operations[len(operations)-*limit:]
+	}
+	operations.Print()
+}
+
+func (c *commandLine) journal() models.Journal {
+	journal, ok := models.As[models.Journal](c.Store)
+	if !ok {
+		log.Fatal("Undo is not configured")
+	}
+	return journal
+}
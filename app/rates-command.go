@@ -0,0 +1,134 @@
+package app
+
+import (
+	"expense-tracker/models"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+const baseCurrencyVariable = "EXPENSE_TRACKER_BASE_CURRENCY"
+
+func (c *commandLine) ratesCommand() {
+	if c.Rates == nil {
+		log.Fatal("Currency rates are not configured")
+	}
+
+	switch flag.Arg(1) {
+	case "set":
+		c.setRateCommand()
+	case "list":
+		c.Rates.Rates().Print()
+	default:
+		fmt.Fprintf(os.Stderr, "Usage of rates:\n")
+		fmt.Fprintf(os.Stderr, "  rates set <from> <to> <rate> [--date YYYY-MM-DD]\n")
+		fmt.Fprintf(os.Stderr, "  rates list\n")
+		os.Exit(1)
+	}
+}
+
+func (c *commandLine) setRateCommand() {
+	setCommand := flag.NewFlagSet("rates set", flag.ExitOnError)
+	date := setCommand.String("date", time.Now().Format(models.DateFormat), "Date the rate is effective from (YYYY-MM-DD)")
+	args := parseInterspersed(setCommand, os.Args[3:])
+
+	if len(args) != 3 {
+		log.Fatal("From currency, to currency and rate are required")
+	}
+
+	from, err := models.ParseCurrency(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	to, err := models.ParseCurrency(args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	value, err := strconv.ParseFloat(args[2], 64)
+	if err != nil {
+		log.Fatal(err)
+	}
+	effective, err := time.Parse(models.DateFormat, *date)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rate := models.Rate{From: from, To: to, Rate: value, Date: effective}
+	if err := c.Rates.Set(rate); err != nil {
+		log.Fatal(err)
+	}
+	rate.Print()
+}
+
+// convert returns the expenses in the base currency and the rates applied,
+// leaving the expenses untouched when they are all in the base currency.
+func (c *commandLine) convert(expenses models.Expenses, base string) (models.Expenses, models.Rates) {
+	if !expenses.HasForeignCurrency(baseCurrency(), base) {
+		return expenses, models.Rates{}
+	}
+	if c.Rates == nil {
+		log.Fatal("Currency rates are not configured")
+	}
+
+	converted, rates, err := c.Rates.Rates().Convert(expenses, baseCurrency(), base)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return converted, rates
+}
+
+// hasForeignCurrency tells whether an expense of the query is in another
+// currency than the base one, without listing them on stores able to scan.
+func (c *commandLine) hasForeignCurrency(query models.Query, base string) bool {
+	foreign := func(expense *models.Expense) bool {
+		return expense.CurrencyOr(baseCurrency()) != base
+	}
+	if scanner, ok := models.As[models.Scanner](c.Store); ok {
+		return scanner.Any(query, foreign)
+	}
+	return c.Store.Find(query).HasForeignCurrency(baseCurrency(), base)
+}
+
+func (c *commandLine) convertedSummary(expenses models.Expenses, base string, groupBy models.GroupBy) {
+	converted, rates := c.convert(expenses, base)
+	if groupBy != "" {
+		models.PrintPeriodSummaries(converted.GroupBy(groupBy))
+	} else {
+		models.PrintCashFlow(converted, base)
+	}
+	printRatesUsed(rates)
+}
+
+func printRatesUsed(rates models.Rates) {
+	if len(rates) == 0 {
+		return
+	}
+	fmt.Printf("\nRates used:\n")
+	rates.Print()
+}
+
+// baseCurrency is the currency of the expenses recorded without one and the
+// default currency summaries are converted to.
+func baseCurrency() string {
+	if currency := os.Getenv(baseCurrencyVariable); currency != "" {
+		return currency
+	}
+	return models.DefaultCurrency
+}
+
+// parseInterspersed parses the flags of a command allowing them to appear
+// before, between or after its positional arguments, which are returned.
+func parseInterspersed(command *flag.FlagSet, args []string) []string {
+	positional := []string{}
+	for {
+		command.Parse(args)
+		if command.NArg() == 0 {
+			return positional
+		}
+		positional = append(positional, command.Arg(0))
+		args = command.Args()[1:]
+	}
+}
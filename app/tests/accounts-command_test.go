@@ -0,0 +1,38 @@
+package tests
+
+import (
+	"expense-tracker/app"
+	"expense-tracker/models"
+	"expense-tracker/stores"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountsCommand(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("✅ should convert the entries of the accounts to the base currency", func(t *testing.T) {
+		// Given
+		directory := t.TempDir()
+		store := stores.NewInMemoryStore()
+		store.Add(models.Expense{Amount: 10000, Description: "Salary", Kind: models.KindIncome, Currency: "EUR", Account: "checking"})
+		store.Add(models.Expense{Amount: 10000, Description: "Refund", Kind: models.KindIncome, Currency: "JPY", Account: "checking"})
+		accounts := stores.NewCsvAccountStore(filepath.Join(directory, "accounts.csv"))
+		accounts.Save(models.Account{Name: "checking"})
+		rates := stores.NewCsvRateStore(filepath.Join(directory, "rates.csv"))
+		yesterday := time.Now().AddDate(0, 0, -1)
+		rates.Set(models.Rate{From: "EUR", To: "USD", Rate: 1.1, Date: yesterday})
+		rates.Set(models.Rate{From: "JPY", To: "USD", Rate: 0.0067, Date: yesterday})
+		commandLine := app.NewCommandLine(store, app.WithAccounts(accounts), app.WithRates(rates))
+
+		// When
+		output := captureOutput(t, func() { run(commandLine, "accounts") })
+
+		// Then
+		asserts.Contains(output, "|checking  |0       |110.67  |")
+		asserts.Contains(output, "Rates used:")
+	})
+}
@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"expense-tracker/app"
+	"expense-tracker/models"
+	"expense-tracker/stores"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnomaliesCommand(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("✅ should compare expenses in the base currency", func(t *testing.T) {
+		// Given
+		store := stores.NewInMemoryStore()
+		store.Add(models.Expense{Amount: 2000, Description: "Lunch", Currency: "USD"})
+		store.Add(models.Expense{Amount: 2100, Description: "Lunch", Currency: "USD"})
+		store.Add(models.Expense{Amount: 1900, Description: "Lunch", Currency: "USD"})
+		store.Add(models.Expense{Amount: 150000, Description: "Lunch", Currency: "JPY"})
+		rates := stores.NewCsvRateStore(filepath.Join(t.TempDir(), "rates.csv"))
+		rates.Set(models.Rate{From: "JPY", To: "USD", Rate: 0.0067, Date: time.Now().AddDate(0, 0, -1)})
+		commandLine := app.NewCommandLine(store, app.WithRates(rates))
+
+		// When
+		output := captureOutput(t, func() { run(commandLine, "anomalies") })
+
+		// Then
+		asserts.Contains(output, "No unusual expenses")
+		asserts.Contains(output, "Rates used:")
+	})
+}
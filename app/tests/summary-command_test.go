@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"expense-tracker/app"
+	"expense-tracker/models"
+	"expense-tracker/stores"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummaryCommand(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("✅ should summarise a streamed ledger without loading it", func(t *testing.T) {
+		// Given
+		directory := t.TempDir()
+		filename := filepath.Join(directory, "test.csv")
+		ledger := stores.NewCsvStore(filename)
+		ledger.Add(models.Expense{Amount: 2000, Description: "Lunch"})
+		ledger.Add(models.Expense{Amount: 3000, Description: "Taxi"})
+		// a head the csv store cannot read makes loading the ledger panic,
+		// while streaming it never reads the head
+		os.WriteFile(filename+".head", []byte("Rows,Head Hash\nnone,none\n"), 0644)
+		audit := stores.NewCsvAuditStore(filepath.Join(directory, "audit.csv"))
+		store := stores.NewJournaledStore(
+			stores.NewAuditedStore(stores.NewStreamingCsvStore(filename), audit, "tester"),
+			filepath.Join(directory, "journal.csv"),
+		)
+		commandLine := app.NewCommandLine(store, app.WithAudit(audit))
+
+		// When
+		var output string
+		asserts.NotPanics(func() {
+			output = captureOutput(t, func() { run(commandLine, "summary") })
+		})
+
+		// Then
+		asserts.Contains(output, "50")
+	})
+}
+
+// run runs the command line with the arguments, as given to the binary.
+func run(commandLine app.CommandLine, args ...string) {
+	arguments := os.Args
+	defer func() { os.Args = arguments }()
+	os.Args = append([]string{"expense-tracker"}, args...)
+	commandLine.Run()
+}
+
+// captureOutput returns what the function prints to the standard output.
+func captureOutput(t *testing.T, print func()) string {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	print()
+	writer.Close()
+	output, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(output)
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (c *commandLine) trashCommand() {
+	switch flag.Arg(1) {
+	case "list":
+		c.Store.Trashed().PrintTrash()
+	case "empty":
+		c.emptyTrashCommand()
+	default:
+		fmt.Fprintf(os.Stderr, "Usage of trash:\n")
+		fmt.Fprintf(os.Stderr, "  trash list\n")
+		fmt.Fprintf(os.Stderr, "  trash empty [--older-than 30d]\n")
+		os.Exit(1)
+	}
+}
+
+func (c *commandLine) emptyTrashCommand() {
+	emptyCommand := flag.NewFlagSet("trash empty", flag.ExitOnError)
+	olderThan := emptyCommand.String("older-than", "0d", "Empty only expenses deleted longer ago, e.g. 30d or 12h")
+	emptyCommand.Parse(os.Args[3:])
+
+	age, err := parseAge(*olderThan)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	emptied, err := c.Store.EmptyTrash(time.Now().Add(-age))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Emptied %d expenses from the trash\n", emptied)
+}
+
+func (c *commandLine) restoreCommand() {
+	restoreCommand := flag.NewFlagSet("restore", flag.ExitOnError)
+	restoreId := restoreCommand.Int("id", 0, "ID of the deleted expense")
+	restoreCommand.Parse(os.Args[2:])
+
+	if *restoreId == 0 {
+		log.Fatal("ID is required")
+	}
+
+	if err := c.Store.Restore(*restoreId); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseAge reads a number of days such as 30d, or any Go duration.
+func parseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		count, err := strconv.Atoi(days)
+		if err != nil || count < 0 {
+			return 0, fmt.Errorf("invalid age %q", value)
+		}
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid age %q", value)
+	}
+	return age, nil
+}
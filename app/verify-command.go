@@ -0,0 +1,28 @@
+package app
+
+import (
+	"expense-tracker/models"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+func (c *commandLine) verifyCommand() {
+	verifyCommand := flag.NewFlagSet("verify", flag.ExitOnError)
+	verifyCommand.Parse(os.Args[2:])
+
+	verifier, ok := models.As[models.Verifier](c.Store)
+	if !ok {
+		log.Fatal("The store cannot be verified")
+	}
+
+	verified, err := verifier.Verify()
+	if err != nil {
+		log.Fatalf("Verified %d entries before: %v", verified, err)
+	}
+	fmt.Printf("Verified %d entries, the ledger is intact\n", verified)
+	if head := verifier.Head(); head != "" {
+		fmt.Printf("Head hash: %s\n", head)
+	}
+}
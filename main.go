@@ -1,10 +1,123 @@
 package main
 
 import (
+	"errors"
 	"expense-tracker/app"
+	"expense-tracker/models"
 	"expense-tracker/stores"
+	"log"
+	"os"
+	"strconv"
+	"time"
 )
 
 func main() {
-	app.NewCommandLine(stores.NewCsvStore("test.csv")).Run()
+	defer exitOnKeyError()
+
+	options := fileOptions()
+	audit := stores.NewCsvAuditStore("audit.csv", options...)
+	ledgerOptions := append(options, stores.WithBackups(backupPolicy()))
+
+	app.NewCommandLine(
+		stores.NewJournaledStore(stores.NewAuditedStore(backend(ledgerOptions), audit, author()), "journal.csv", options...),
+		app.WithRates(stores.NewCsvRateStore("rates.csv", options...)),
+		app.WithAccounts(stores.NewCsvAccountStore("accounts.csv", options...)),
+		app.WithBudgets(stores.NewCsvBudgetStore("budgets.csv", options...)),
+		app.WithAudit(audit),
+		app.WithExporter(stores.NewFileExporter()),
+	).Run()
+}
+
+// backend is the store of the expenses, the CSV file unless another is chosen
+// with EXPENSE_TRACKER_BACKEND: events for the event log, partitioned for the
+// files by year, or streaming to query the CSV file without loading it.
+func backend(options []stores.FileOption) models.Store {
+	switch os.Getenv("EXPENSE_TRACKER_BACKEND") {
+	case "events":
+		return stores.NewEventStore("events.csv", options...)
+	case "partitioned":
+		return stores.NewPartitionedStore("ledger", options...)
+	case "streaming":
+		return stores.NewStreamingCsvStore(ledger(), options...)
+	}
+	return stores.NewCsvStore(ledger(), options...)
+}
+
+// ledger is the CSV file of the expenses, test.csv unless another, such as a
+// compressed archive ending with .csv.gz, is named by EXPENSE_TRACKER_LEDGER.
+func ledger() string {
+	if filename := os.Getenv("EXPENSE_TRACKER_LEDGER"); filename != "" {
+		return filename
+	}
+	return "test.csv"
+}
+
+// fileOptions encrypt the files of the expenses, the audit trail, the rates,
+// the accounts and the budgets with the key of the file named by
+// EXPENSE_TRACKER_KEY_FILE, or one derived from EXPENSE_TRACKER_PASSPHRASE.
+func fileOptions() []stores.FileOption {
+	var codec models.Codec
+	var err error
+	switch {
+	case os.Getenv("EXPENSE_TRACKER_KEY_FILE") != "":
+		key, keyErr := stores.ReadKeyFile(os.Getenv("EXPENSE_TRACKER_KEY_FILE"))
+		if keyErr != nil {
+			log.Fatal(keyErr)
+		}
+		codec, err = stores.NewKeyCipher(key)
+	case os.Getenv("EXPENSE_TRACKER_PASSPHRASE") != "":
+		codec, err = stores.NewPassphraseCipher(os.Getenv("EXPENSE_TRACKER_PASSPHRASE"))
+	default:
+		return nil
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	return []stores.FileOption{stores.WithCodec(codec)}
+}
+
+// exitOnKeyError ends with the reason rather than a stack trace when an
+// encrypted file is opened without its key or with another one, which the
+// stores panic on.
+func exitOnKeyError() {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	if err, ok := recovered.(error); ok && (errors.Is(err, stores.ErrKeyRequired) || errors.Is(err, stores.ErrWrongKey)) {
+		log.Fatal(err)
+	}
+	panic(recovered)
+}
+
+// backupPolicy keeps EXPENSE_TRACKER_BACKUPS backups of the ledger, 10 by
+// default, for EXPENSE_TRACKER_BACKUP_DAYS days if set.
+func backupPolicy() stores.BackupPolicy {
+	policy := stores.BackupPolicy{Count: stores.DefaultBackupCount}
+	if count := os.Getenv("EXPENSE_TRACKER_BACKUPS"); count != "" {
+		parsed, err := strconv.Atoi(count)
+		if err != nil || parsed < 0 {
+			log.Fatalf("Invalid EXPENSE_TRACKER_BACKUPS %q", count)
+		}
+		policy.Count = parsed
+	}
+	if days := os.Getenv("EXPENSE_TRACKER_BACKUP_DAYS"); days != "" {
+		parsed, err := strconv.Atoi(days)
+		if err != nil || parsed < 0 {
+			log.Fatalf("Invalid EXPENSE_TRACKER_BACKUP_DAYS %q", days)
+		}
+		policy.MaxAge = time.Duration(parsed) * 24 * time.Hour
+	}
+	return policy
+}
+
+// author is who changes are recorded as made by in the audit trail.
+func author() string {
+	if name := os.Getenv("EXPENSE_TRACKER_USER"); name != "" {
+		return name
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
 }
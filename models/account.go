@@ -0,0 +1,95 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+type (
+	Account struct {
+		Name           string
+		OpeningBalance int
+	}
+
+	Accounts []Account
+
+	AccountStore interface {
+		Save(account Account) error
+		Accounts() Accounts
+	}
+
+	AccountBalance struct {
+		Account
+		Balance int
+	}
+)
+
+const (
+	AccountHeaderFormat = "|Account   |Opening |Balance |"
+	AccountStringFormat = "|%-10s|%-8s|%-8s|\n"
+)
+
+func (a Accounts) Find(name string) (Account, bool) {
+	for _, account := range a {
+		if account.Name == name {
+			return account, true
+		}
+	}
+	return Account{}, false
+}
+
+// Balances returns the current balance of every defined account, plus the
+// accounts only referenced by entries, starting from their opening balance.
+// Amounts are added as they are, so entries in several currencies are to be
+// converted to the currency of the opening balances first.
+func (a Accounts) Balances(entries Expenses) []AccountBalance {
+	balances := map[string]*AccountBalance{}
+	balanceOf := func(name string) *AccountBalance {
+		if balance, ok := balances[name]; ok {
+			return balance
+		}
+		account, _ := a.Find(name)
+		account.Name = name
+		balances[name] = &AccountBalance{Account: account, Balance: account.OpeningBalance}
+		return balances[name]
+	}
+
+	for _, account := range a {
+		balanceOf(account.Name)
+	}
+
+	for _, entry := range entries {
+		if entry.Account == "" {
+			continue
+		}
+		switch entry.TransactionKind() {
+		case KindExpense:
+			balanceOf(entry.Account).Balance -= entry.Amount
+		case KindIncome, KindRefund:
+			balanceOf(entry.Account).Balance += entry.Amount
+		case KindTransfer:
+			balanceOf(entry.Account).Balance -= entry.Amount
+			balanceOf(entry.ToAccount).Balance += entry.Amount
+		}
+	}
+
+	result := make([]AccountBalance, 0, len(balances))
+	for _, balance := range balances {
+		result = append(result, *balance)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func (b AccountBalance) Print() {
+	fmt.Printf(AccountStringFormat, b.Name, FormatAmount(b.OpeningBalance), FormatAmount(b.Balance))
+}
+
+func PrintAccountBalances(balances []AccountBalance) {
+	fmt.Printf(AccountHeaderFormat + "\n")
+	for _, balance := range balances {
+		balance.Print()
+	}
+}
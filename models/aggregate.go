@@ -0,0 +1,91 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+type Aggregation struct {
+	Count  int
+	Total  int
+	Min    int
+	Max    int
+	Mean   float64
+	Median float64
+	P90    float64
+	StdDev float64
+}
+
+func (e Expenses) Aggregate() Aggregation {
+	aggregation := Aggregation{Count: len(e)}
+	if len(e) == 0 {
+		return aggregation
+	}
+
+	amounts := make([]int, 0, len(e))
+	for _, expense := range e {
+		amounts = append(amounts, expense.Amount)
+		aggregation.Total += expense.Amount
+	}
+	sort.Ints(amounts)
+
+	aggregation.Min = amounts[0]
+	aggregation.Max = amounts[len(amounts)-1]
+	aggregation.Mean = float64(aggregation.Total) / float64(aggregation.Count)
+	aggregation.Median = Percentile(amounts, 50)
+	aggregation.P90 = Percentile(amounts, 90)
+
+	variance := 0.0
+	for _, amount := range amounts {
+		variance += math.Pow(float64(amount)-aggregation.Mean, 2)
+	}
+	aggregation.StdDev = math.Sqrt(variance / float64(aggregation.Count))
+
+	return aggregation
+}
+
+// Percentile interpolates linearly between the closest ranks of an already
+// sorted slice.
+func Percentile(sorted []int, percentile float64) float64 {
+	values := make([]float64, 0, len(sorted))
+	for _, value := range sorted {
+		values = append(values, float64(value))
+	}
+	return percentileOf(values, percentile)
+}
+
+func percentileOf(sorted []float64, percentile float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := percentile / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	weight := rank - float64(lower)
+	return sorted[lower]*(1-weight) + sorted[upper]*weight
+}
+
+func (e Expenses) Largest(n int) Expenses {
+	return e.Find(Query{SortBy: SortByAmount, Desc: true, Limit: n})
+}
+
+func (a Aggregation) Print() {
+	fmt.Printf("Count: %d\n", a.Count)
+	fmt.Printf("Total: %s\n", FormatAmount(a.Total))
+	fmt.Printf("Mean: %s\n", FormatAverage(a.Mean))
+	fmt.Printf("Median: %s\n", FormatAverage(a.Median))
+	fmt.Printf("P90: %s\n", FormatAverage(a.P90))
+	fmt.Printf("Min: %s\n", FormatAmount(a.Min))
+	fmt.Printf("Max: %s\n", FormatAmount(a.Max))
+	fmt.Printf("Std Dev: %s\n", FormatAverage(a.StdDev))
+}
+
+func PrintStats(expenses Expenses, top int) {
+	expenses.Aggregate().Print()
+	if top <= 0 || len(expenses) == 0 {
+		return
+	}
+	fmt.Printf("\nLargest expenses:\n")
+	expenses.Largest(top).Print()
+}
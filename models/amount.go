@@ -0,0 +1,129 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// amounts are whole numbers of hundredths of their currency, so that cents
+// add up exactly. They are written in files and on the command line in units
+// of the currency, with up to two decimals, whole amounts without any.
+const AmountScale = 100
+
+// ParseAmount reads an amount such as 20, 12.5 or -3.20 into hundredths.
+func ParseAmount(value string) (int, error) {
+	text := strings.TrimSpace(value)
+	negative := strings.HasPrefix(text, "-")
+	units, cents, hasCents := strings.Cut(strings.TrimPrefix(text, "-"), ".")
+	if units == "" || strings.ContainsAny(units, "+-") || (hasCents && (cents == "" || len(cents) > 2)) {
+		return 0, fmt.Errorf("invalid amount %q, expected at most two decimals", value)
+	}
+
+	whole, err := strconv.Atoi(units)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q, expected at most two decimals", value)
+	}
+	if whole > (math.MaxInt-(AmountScale-1))/AmountScale {
+		return 0, fmt.Errorf("invalid amount %q, too large", value)
+	}
+	fraction := 0
+	if hasCents {
+		fraction, err = strconv.Atoi((cents + "0")[:2])
+		if err != nil || strings.ContainsAny(cents, "+-") {
+			return 0, fmt.Errorf("invalid amount %q, expected at most two decimals", value)
+		}
+	}
+
+	amount := whole*AmountScale + fraction
+	if negative {
+		return -amount, nil
+	}
+	return amount, nil
+}
+
+// FormatAmount writes hundredths as units of the currency, leaving out the
+// decimals of whole amounts.
+func FormatAmount(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	if amount%AmountScale == 0 {
+		return sign + strconv.Itoa(amount/AmountScale)
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/AmountScale, amount%AmountScale)
+}
+
+// formatDelta writes a change of amount with its sign.
+func formatDelta(amount int) string {
+	if amount < 0 {
+		return FormatAmount(amount)
+	}
+	return "+" + FormatAmount(amount)
+}
+
+// FormatAverage writes a mean or median of hundredths as units of the
+// currency, with two decimals.
+func FormatAverage(amount float64) string {
+	return strconv.FormatFloat(math.Round(amount)/AmountScale, 'f', 2, 64)
+}
+
+// jsonAmount writes hundredths in JSON as a decimal number of units, like in
+// the CSV files, so journals and exports read the same as the ledger.
+type jsonAmount int
+
+func (a jsonAmount) MarshalJSON() ([]byte, error) {
+	return []byte(FormatAmount(int(a))), nil
+}
+
+func (a *jsonAmount) UnmarshalJSON(data []byte) error {
+	amount, err := ParseAmount(string(data))
+	if err != nil {
+		return err
+	}
+	*a = jsonAmount(amount)
+	return nil
+}
+
+type expenseAlias Expense
+
+type expenseJson struct {
+	*expenseAlias
+	Amount jsonAmount
+}
+
+func (e Expense) MarshalJSON() ([]byte, error) {
+	alias := expenseAlias(e)
+	return json.Marshal(expenseJson{expenseAlias: &alias, Amount: jsonAmount(e.Amount)})
+}
+
+func (e *Expense) UnmarshalJSON(data []byte) error {
+	value := expenseJson{expenseAlias: (*expenseAlias)(e)}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	e.Amount = int(value.Amount)
+	return nil
+}
+
+type shareJson struct {
+	Person string
+	Amount jsonAmount
+}
+
+func (s Share) MarshalJSON() ([]byte, error) {
+	return json.Marshal(shareJson{Person: s.Person, Amount: jsonAmount(s.Amount)})
+}
+
+func (s *Share) UnmarshalJSON(data []byte) error {
+	value := shareJson{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = Share{Person: value.Person, Amount: int(value.Amount)}
+	return nil
+}
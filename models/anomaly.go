@@ -0,0 +1,123 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+type Anomaly struct {
+	Expense *Expense
+	Group   string
+	Median  float64
+	Score   float64
+}
+
+const (
+	// DefaultAnomalyThreshold is the modified z-score above which an amount is
+	// unusual, as commonly recommended for the median absolute deviation.
+	DefaultAnomalyThreshold = 3.5
+	// MinAnomalySamples is the history needed before judging an amount.
+	MinAnomalySamples = 3
+
+	madConsistency     = 0.6745
+	meanAbsConsistency = 0.7979
+)
+
+// AnomalyGroup is what an expense is compared against: its category, or its
+// description for uncategorized expenses.
+func (e *Expense) AnomalyGroup() string {
+	if e.Category != "" {
+		return strings.ToLower(e.Category)
+	}
+	return strings.ToLower(e.Description)
+}
+
+// Anomalies returns the expenses much larger than the others of their group,
+// largest score first.
+func (e Expenses) Anomalies(threshold float64) []Anomaly {
+	anomalies := []Anomaly{}
+	for _, expense := range e.OfKind(KindExpense) {
+		if anomaly, ok := e.Check(expense, threshold); ok {
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		return anomalies[i].Score > anomalies[j].Score
+	})
+	return anomalies
+}
+
+// Check scores an expense against the other expenses of its group in its
+// currency using the modified z-score over the median absolute deviation, so
+// expenses are best converted to a single currency first. Only amounts above
+// the norm are reported.
+func (e Expenses) Check(candidate *Expense, threshold float64) (Anomaly, bool) {
+	group := candidate.AnomalyGroup()
+	amounts := []int{}
+	for _, expense := range e.OfKind(KindExpense) {
+		if expense == candidate || (candidate.Id != 0 && expense.Id == candidate.Id) {
+			continue
+		}
+		if expense.AnomalyGroup() == group && expense.Currency == candidate.Currency {
+			amounts = append(amounts, expense.Amount)
+		}
+	}
+	if len(amounts) < MinAnomalySamples {
+		return Anomaly{}, false
+	}
+
+	sort.Ints(amounts)
+	median := Percentile(amounts, 50)
+	amount := float64(candidate.Amount)
+	if amount <= median {
+		return Anomaly{}, false
+	}
+
+	deviations := make([]float64, 0, len(amounts))
+	meanDeviation := 0.0
+	for _, value := range amounts {
+		deviation := math.Abs(float64(value) - median)
+		deviations = append(deviations, deviation)
+		meanDeviation += deviation / float64(len(amounts))
+	}
+	sort.Float64s(deviations)
+
+	// fall back to the mean absolute deviation when most amounts are equal,
+	// and to an infinite score when they all are
+	score := math.Inf(1)
+	if mad := percentileOf(deviations, 50); mad > 0 {
+		score = madConsistency * (amount - median) / mad
+	} else if meanDeviation > 0 {
+		score = meanAbsConsistency * (amount - median) / meanDeviation
+	}
+
+	if score <= threshold {
+		return Anomaly{}, false
+	}
+	return Anomaly{Expense: candidate, Group: group, Median: median, Score: score}, true
+}
+
+func (a Anomaly) String() string {
+	score := fmt.Sprintf("%.1f", a.Score)
+	if math.IsInf(a.Score, 1) {
+		score = "inf"
+	}
+	return fmt.Sprintf("%s is unusual for %s (median %s, score %s)", FormatAmount(a.Expense.Amount), a.Group, FormatAmount(int(math.Round(a.Median))), score)
+}
+
+func PrintAnomalies(anomalies []Anomaly) {
+	if len(anomalies) == 0 {
+		fmt.Printf("No unusual expenses\n")
+		return
+	}
+	fmt.Printf(HeaderFormat + "\n")
+	for _, anomaly := range anomalies {
+		anomaly.Expense.Print()
+	}
+	fmt.Printf("\n")
+	for _, anomaly := range anomalies {
+		fmt.Printf("#%d: %s\n", anomaly.Expense.Id, anomaly)
+	}
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type (
+	// Revision is the state of an expense after a change, with who made it
+	// and when.
+	Revision struct {
+		ExpenseId int
+		Version   int
+		Action    Action
+		Author    string
+		At        time.Time
+		Expense   Expense
+	}
+
+	Revisions []Revision
+
+	AuditStore interface {
+		Record(revision Revision) error
+		Revisions(expenseId int) Revisions
+	}
+
+	Change struct {
+		Field string
+		From  string
+		To    string
+	}
+)
+
+// ActionPurge is the removal of a trashed expense for good.
+const ActionPurge Action = "purge"
+
+var auditedFields = []struct {
+	name  string
+	value func(e *Expense) string
+}{
+	{"description", func(e *Expense) string { return e.Description }},
+	{"amount", func(e *Expense) string { return FormatAmount(e.Amount) }},
+	{"category", func(e *Expense) string { return e.Category }},
+	{"currency", func(e *Expense) string { return e.Currency }},
+	{"kind", func(e *Expense) string { return string(e.Kind) }},
+	{"refund of", func(e *Expense) string { return formatId(e.RefundOf) }},
+	{"account", func(e *Expense) string { return e.Account }},
+	{"to account", func(e *Expense) string { return e.ToAccount }},
+	{"paid by", func(e *Expense) string { return e.PaidBy }},
+	{"split", func(e *Expense) string { return e.Split.String() }},
+	{"recurring", func(e *Expense) string { return formatFlag(e.Recurring) }},
+	{"deleted at", func(e *Expense) string { return formatDate(e.DeletedAt) }},
+}
+
+// Diff lists the fields changed from one state of an expense to the next,
+// before being nil for a new expense.
+func Diff(before *Expense, after *Expense) []Change {
+	changes := []Change{}
+	for _, field := range auditedFields {
+		from := ""
+		if before != nil {
+			from = field.value(before)
+		}
+		if to := field.value(after); from != to {
+			changes = append(changes, Change{Field: field.name, From: from, To: to})
+		}
+	}
+	return changes
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %q -> %q", c.Field, c.From, c.To)
+}
+
+// Print shows every revision with what it changed from the previous one.
+func (r Revisions) Print() {
+	var previous *Expense
+	for _, revision := range r {
+		fmt.Printf("Version %d: %s by %s at %s\n", revision.Version, revision.Action, revision.Author, revision.At.Format(time.DateTime))
+		for _, change := range Diff(previous, &revision.Expense) {
+			fmt.Printf("  %s\n", change)
+		}
+		previous = &revision.Expense
+	}
+}
+
+func formatId(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
+
+func formatFlag(flag bool) string {
+	if !flag {
+		return ""
+	}
+	return strconv.FormatBool(flag)
+}
+
+func formatDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format(DateFormat)
+}
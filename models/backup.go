@@ -0,0 +1,88 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type (
+	Backup struct {
+		Id        string
+		CreatedAt time.Time
+		Size      int64
+	}
+
+	// Backuper is implemented by stores keeping backups of their file.
+	Backuper interface {
+		Backups() ([]Backup, error)
+		CreateBackup() (Backup, error)
+		BackupExpenses(id string) (Expenses, error)
+		RestoreBackup(id string) error
+	}
+
+	// ExpenseDiff is how an expense differs from one list of expenses to
+	// another, Changes being empty for added and removed expenses.
+	ExpenseDiff struct {
+		Id      int
+		Status  string
+		Changes []Change
+	}
+)
+
+const (
+	BackupHeaderFormat = "|ID                 |Created At         |Size    |"
+	BackupStringFormat = "|%-19s|%-19s|%-8d|\n"
+
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// DiffExpenses lists the expenses added, removed or changed going from one
+// list of expenses to another, in the order of their IDs.
+func DiffExpenses(from Expenses, to Expenses) []ExpenseDiff {
+	before := map[int]*Expense{}
+	for _, expense := range from {
+		before[expense.Id] = expense
+	}
+
+	diffs := []ExpenseDiff{}
+	seen := map[int]bool{}
+	for _, expense := range to.Find(Query{}) {
+		seen[expense.Id] = true
+		previous, ok := before[expense.Id]
+		if !ok {
+			diffs = append(diffs, ExpenseDiff{Id: expense.Id, Status: DiffAdded})
+			continue
+		}
+		if changes := Diff(previous, expense); len(changes) > 0 {
+			diffs = append(diffs, ExpenseDiff{Id: expense.Id, Status: DiffChanged, Changes: changes})
+		}
+	}
+	for _, expense := range from.Find(Query{}) {
+		if !seen[expense.Id] {
+			diffs = append(diffs, ExpenseDiff{Id: expense.Id, Status: DiffRemoved})
+		}
+	}
+	return diffs
+}
+
+func PrintBackups(backups []Backup) {
+	fmt.Printf(BackupHeaderFormat + "\n")
+	for _, backup := range backups {
+		fmt.Printf(BackupStringFormat, backup.Id, backup.CreatedAt.Format(time.DateTime), backup.Size)
+	}
+}
+
+func PrintExpenseDiffs(diffs []ExpenseDiff) {
+	if len(diffs) == 0 {
+		fmt.Printf("No changes\n")
+		return
+	}
+	for _, diff := range diffs {
+		fmt.Printf("#%d %s\n", diff.Id, diff.Status)
+		for _, change := range diff.Changes {
+			fmt.Printf("  %s\n", change)
+		}
+	}
+}
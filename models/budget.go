@@ -0,0 +1,175 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// Budget is the monthly amount for a category from a month onwards, until
+	// another budget for the same category replaces it. With rollover, what is
+	// left unspent at the end of a month is added to the next one.
+	Budget struct {
+		Category string
+		Amount   int
+		Rollover bool
+		From     time.Time
+	}
+
+	Budgets []Budget
+
+	BudgetStore interface {
+		Set(budget Budget) error
+		Budgets() Budgets
+	}
+
+	BudgetStatus struct {
+		Category  string
+		Budget    int
+		Rollover  int
+		Spent     int
+		Remaining int
+		Percent   float64
+	}
+)
+
+const (
+	BudgetHeaderFormat = "|Category  |Budget|Rollover|Spent |Remaining|Percent|"
+	BudgetStringFormat = "|%-10s|%-6s|%-8s|%-6s|%-9s|%-7s|"
+
+	BudgetWarningPercent = 80
+
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+	colorGreen  = "\033[32m"
+)
+
+func MonthStart(year int, month time.Month) time.Time {
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// For returns the budget of the category effective in the month.
+func (b Budgets) For(category string, month time.Time) (Budget, bool) {
+	found := false
+	var effective Budget
+	for _, budget := range b {
+		if !strings.EqualFold(budget.Category, category) || budget.From.After(month) {
+			continue
+		}
+		if !found || budget.From.After(effective.From) {
+			effective = budget
+			found = true
+		}
+	}
+	return effective, found
+}
+
+func (b Budgets) Categories() []string {
+	seen := map[string]bool{}
+	categories := []string{}
+	for _, budget := range b {
+		category := strings.ToLower(budget.Category)
+		if !seen[category] {
+			seen[category] = true
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// Status returns the budget status of every category with a budget in the
+// month. Spending is accounted per month the same way as the monthly summary,
+// from expenses converted to the currency of the budgets.
+func (b Budgets) Status(expenses Expenses, month time.Time) []BudgetStatus {
+	statuses := []BudgetStatus{}
+	for _, category := range b.Categories() {
+		budget, ok := b.For(category, month)
+		if !ok {
+			continue
+		}
+
+		rollover := b.rollover(expenses, category, month)
+		spent := spentIn(expenses, category, month)
+		available := budget.Amount + rollover
+
+		status := BudgetStatus{
+			Category:  category,
+			Budget:    budget.Amount,
+			Rollover:  rollover,
+			Spent:     spent,
+			Remaining: available - spent,
+		}
+		if available > 0 {
+			status.Percent = float64(spent) / float64(available) * 100
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// rollover walks the months from the first budget of the category up to the
+// given one, carrying what was left unspent while budgets allow it.
+func (b Budgets) rollover(expenses Expenses, category string, month time.Time) int {
+	first, ok := b.first(category)
+	if !ok {
+		return 0
+	}
+
+	carried := 0
+	for current := first; current.Before(month); current = current.AddDate(0, 1, 0) {
+		budget, ok := b.For(category, current)
+		if !ok || !budget.Rollover {
+			carried = 0
+			continue
+		}
+		carried = max(0, budget.Amount+carried-spentIn(expenses, category, current))
+	}
+	return carried
+}
+
+func (b Budgets) first(category string) (time.Time, bool) {
+	found := false
+	var first time.Time
+	for _, budget := range b {
+		if strings.EqualFold(budget.Category, category) && (!found || budget.From.Before(first)) {
+			first = budget.From
+			found = true
+		}
+	}
+	return first, found
+}
+
+func spentIn(expenses Expenses, category string, month time.Time) int {
+	return expenses.Find(Query{Category: category}.ForMonth(month.Month(), month.Year())).Spending()
+}
+
+func (s BudgetStatus) Print(colored bool) {
+	row := fmt.Sprintf(BudgetStringFormat, s.Category, FormatAmount(s.Budget), FormatAmount(s.Rollover), FormatAmount(s.Spent), FormatAmount(s.Remaining), fmt.Sprintf("%.1f%%", s.Percent))
+	if !colored {
+		fmt.Println(row)
+		return
+	}
+
+	color := colorGreen
+	if s.Percent >= BudgetWarningPercent {
+		color = colorYellow
+	}
+	if s.Remaining < 0 {
+		color = colorRed
+	}
+	fmt.Println(color + row + colorReset)
+}
+
+func PrintBudgetStatuses(statuses []BudgetStatus, colored bool) {
+	fmt.Printf(BudgetHeaderFormat + "\n")
+	for _, status := range statuses {
+		status.Print(colored)
+		if status.Remaining < 0 {
+			fmt.Printf("Warning: %s is over budget by %s\n", status.Category, FormatAmount(-status.Remaining))
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Bar struct {
+	Label string
+	Value int
+}
+
+const (
+	DefaultChartWidth = 80
+	Uncategorized     = "uncategorized"
+)
+
+var (
+	barBlocks       = []rune{'▏', '▎', '▍', '▌', '▋', '▊', '▉', '█'}
+	sparklineBlocks = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+)
+
+func PeriodBars(summaries []PeriodSummary) []Bar {
+	bars := make([]Bar, 0, len(summaries))
+	for _, summary := range summaries {
+		bars = append(bars, Bar{Label: summary.Period, Value: summary.Total})
+	}
+	return bars
+}
+
+func (e Expenses) CategoryBars() []Bar {
+	totals := map[string]int{}
+	for _, expense := range e.spendingEntries() {
+		category := expense.Category
+		if category == "" {
+			category = Uncategorized
+		}
+		totals[category] += expense.Spent()
+	}
+
+	bars := make([]Bar, 0, len(totals))
+	for category, total := range totals {
+		bars = append(bars, Bar{Label: category, Value: total})
+	}
+	sort.Slice(bars, func(i, j int) bool {
+		if bars[i].Value != bars[j].Value {
+			return bars[i].Value > bars[j].Value
+		}
+		return bars[i].Label < bars[j].Label
+	})
+	return bars
+}
+
+// DailyTotals returns the spending of every day between the first and the
+// last expense or refund, days without any included as zero.
+func (e Expenses) DailyTotals() []int {
+	e = e.spendingEntries()
+	if len(e) == 0 {
+		return []int{}
+	}
+
+	first, last := day(e[0].SummaryDate()), day(e[0].SummaryDate())
+	for _, expense := range e {
+		date := day(expense.SummaryDate())
+		if date.Before(first) {
+			first = date
+		}
+		if date.After(last) {
+			last = date
+		}
+	}
+
+	totals := make([]int, int(last.Sub(first).Hours()/24)+1)
+	for _, expense := range e {
+		totals[int(day(expense.SummaryDate()).Sub(first).Hours()/24)] += expense.Spent()
+	}
+	return totals
+}
+
+func day(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func RenderBarChart(bars []Bar, width int) string {
+	labelWidth, valueWidth, highest := 0, 0, 0
+	for _, bar := range bars {
+		labelWidth = max(labelWidth, len([]rune(bar.Label)))
+		valueWidth = max(valueWidth, len(FormatAmount(bar.Value)))
+		highest = max(highest, bar.Value)
+	}
+
+	// label, value and the separating spaces
+	barWidth := width - labelWidth - valueWidth - 3
+	if barWidth < 1 {
+		barWidth = 1
+	}
+
+	var builder strings.Builder
+	for _, bar := range bars {
+		fmt.Fprintf(&builder, "%-*s %s %*s\n", labelWidth, bar.Label, renderBar(bar.Value, highest, barWidth), valueWidth, FormatAmount(bar.Value))
+	}
+	return builder.String()
+}
+
+func renderBar(value int, highest int, width int) string {
+	if highest <= 0 || value <= 0 {
+		return strings.Repeat(" ", width)
+	}
+
+	eighths := value * width * len(barBlocks) / highest
+	full := eighths / len(barBlocks)
+	bar := strings.Repeat(string(barBlocks[len(barBlocks)-1]), full)
+	if remainder := eighths % len(barBlocks); remainder > 0 {
+		bar += string(barBlocks[remainder-1])
+		full++
+	}
+	return bar + strings.Repeat(" ", width-full)
+}
+
+// Sparkline renders one block per value, keeping only the most recent values
+// when there are more than width.
+func Sparkline(values []int, width int) string {
+	if width > 0 && len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	highest := 0
+	for _, value := range values {
+		highest = max(highest, value)
+	}
+
+	var builder strings.Builder
+	for _, value := range values {
+		if highest <= 0 || value <= 0 {
+			builder.WriteRune(sparklineBlocks[0])
+			continue
+		}
+		builder.WriteRune(sparklineBlocks[value*(len(sparklineBlocks)-1)/highest])
+	}
+	return builder.String()
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	Rate struct {
+		From string
+		To   string
+		Rate float64
+		Date time.Time
+	}
+
+	Rates []Rate
+
+	RateStore interface {
+		Set(rate Rate) error
+		Rates() Rates
+	}
+)
+
+const DefaultCurrency = "USD"
+
+func ParseCurrency(value string) (string, error) {
+	currency := strings.ToUpper(strings.TrimSpace(value))
+	if len(currency) != 3 {
+		return "", fmt.Errorf("invalid currency %q, expected a 3 letter code", value)
+	}
+	for _, letter := range currency {
+		if letter < 'A' || letter > 'Z' {
+			return "", fmt.Errorf("invalid currency %q, expected a 3 letter code", value)
+		}
+	}
+	return currency, nil
+}
+
+// CurrencyOr returns the currency of the expense, falling back to the given
+// one for expenses recorded without a currency.
+func (e *Expense) CurrencyOr(fallback string) string {
+	if e.Currency == "" {
+		return fallback
+	}
+	return e.Currency
+}
+
+func (e Expenses) HasForeignCurrency(fallback string, base string) bool {
+	for _, expense := range e {
+		if expense.CurrencyOr(fallback) != base {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns the most recent rate from one currency to another effective
+// on the given date. Inverse rates are used when only the opposite direction
+// was recorded.
+func (r Rates) Lookup(from string, to string, date time.Time) (Rate, error) {
+	if from == to {
+		return Rate{From: from, To: to, Rate: 1, Date: date}, nil
+	}
+
+	found := false
+	var best Rate
+	for _, rate := range r {
+		candidate := rate
+		switch {
+		case rate.From == from && rate.To == to:
+		case rate.From == to && rate.To == from:
+			candidate = Rate{From: from, To: to, Rate: 1 / rate.Rate, Date: rate.Date}
+		default:
+			continue
+		}
+		if candidate.Date.After(date) {
+			continue
+		}
+		if !found || candidate.Date.After(best.Date) {
+			best = candidate
+			found = true
+		}
+	}
+
+	if !found {
+		return Rate{}, fmt.Errorf("no rate from %s to %s on %s", from, to, date.Format(DateFormat))
+	}
+	return best, nil
+}
+
+// Convert returns copies of the expenses with their amounts in the base
+// currency, together with the distinct rates that were applied. Each expense
+// is converted at the rate of the day it was spent, even when it was updated
+// later. Expenses without a currency are taken to be in the fallback one.
+func (r Rates) Convert(expenses Expenses, fallback string, base string) (Expenses, Rates, error) {
+	converted := make(Expenses, 0, len(expenses))
+	used := map[Rate]bool{}
+	for _, expense := range expenses {
+		currency := expense.CurrencyOr(fallback)
+		rate, err := r.Lookup(currency, base, expense.CreatedAt)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		copied := *expense
+		copied.Amount = int(math.Round(float64(expense.Amount) * rate.Rate))
+		copied.Split = expense.Split.convert(rate.Rate, copied.Amount)
+		copied.Currency = base
+		converted = append(converted, &copied)
+
+		if currency != base {
+			used[rate] = true
+		}
+	}
+
+	rates := Rates{}
+	for rate := range used {
+		rates = append(rates, rate)
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].From != rates[j].From {
+			return rates[i].From < rates[j].From
+		}
+		return rates[i].Date.Before(rates[j].Date)
+	})
+
+	return converted, rates, nil
+}
+
+// convert applies the rate to the shares, giving the rounding difference to
+// the last one so that they still add up to the converted amount.
+func (s Shares) convert(rate float64, amount int) Shares {
+	if len(s) == 0 {
+		return s
+	}
+	converted := make(Shares, len(s))
+	total := 0
+	for i, share := range s {
+		converted[i] = Share{Person: share.Person, Amount: int(math.Round(float64(share.Amount) * rate))}
+		total += converted[i].Amount
+	}
+	converted[len(converted)-1].Amount += amount - total
+	return converted
+}
+
+func (r Rate) Print() {
+	fmt.Printf("1 %s = %.6g %s (%s)\n", r.From, r.Rate, r.To, r.Date.Format(DateFormat))
+}
+
+func (r Rates) Print() {
+	for _, rate := range r {
+		rate.Print()
+	}
+}
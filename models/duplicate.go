@@ -0,0 +1,135 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultDuplicateDays = 1
+	// maxDescriptionDistance is the number of edits under which two
+	// descriptions are taken as the same, to catch typos.
+	maxDescriptionDistance = 2
+)
+
+// IsLikelyDuplicate tells whether two entries look like the same one entered
+// twice: same kind, amount and currency, similar description and created
+// within days of each other.
+func (e *Expense) IsLikelyDuplicate(other *Expense, days int) bool {
+	if e.TransactionKind() != other.TransactionKind() || e.Amount != other.Amount || e.Currency != other.Currency {
+		return false
+	}
+	if !similarDescriptions(e.Description, other.Description) {
+		return false
+	}
+	gap := day(e.CreatedAt).Sub(day(other.CreatedAt))
+	if gap < 0 {
+		gap = -gap
+	}
+	return gap <= time.Duration(days)*24*time.Hour
+}
+
+func (e Expenses) DuplicatesOf(candidate *Expense, days int) Expenses {
+	duplicates := Expenses{}
+	for _, expense := range e {
+		if expense != candidate && expense.Id != candidate.Id && candidate.IsLikelyDuplicate(expense, days) {
+			duplicates = append(duplicates, expense)
+		}
+	}
+	return duplicates
+}
+
+// DuplicateGroups returns the groups of entries that are likely duplicates of
+// one another, each group in the order of the entries.
+func (e Expenses) DuplicateGroups(days int) []Expenses {
+	groups := []Expenses{}
+	grouped := map[*Expense]bool{}
+	for i, expense := range e {
+		if grouped[expense] {
+			continue
+		}
+		group := Expenses{expense}
+		for _, other := range e[i+1:] {
+			if !grouped[other] && expense.IsLikelyDuplicate(other, days) {
+				group = append(group, other)
+				grouped[other] = true
+			}
+		}
+		if len(group) > 1 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// ValidateMerge checks the IDs of a merge before anything is changed, the
+// first one being the entry kept: it cannot be merged into itself and no
+// duplicate can be merged twice.
+func ValidateMerge(ids []int) error {
+	if len(ids) < 2 {
+		return fmt.Errorf("at least two IDs are required to merge")
+	}
+	merged := map[int]bool{}
+	for _, id := range ids {
+		if !merged[id] {
+			merged[id] = true
+			continue
+		}
+		if id == ids[0] {
+			return fmt.Errorf("expense with ID %d cannot be merged into itself", id)
+		}
+		return fmt.Errorf("expense with ID %d is given more than once", id)
+	}
+	return nil
+}
+
+// Merge fills in what the kept entry is missing from its duplicates.
+func (e *Expense) Merge(duplicates Expenses) {
+	for _, duplicate := range duplicates {
+		if e.Category == "" {
+			e.Category = duplicate.Category
+		}
+		if e.Account == "" {
+			e.Account = duplicate.Account
+		}
+	}
+}
+
+func similarDescriptions(a string, b string) bool {
+	a = normalizeDescription(a)
+	b = normalizeDescription(b)
+	if a == b {
+		return true
+	}
+	if len(a) < 4 || len(b) < 4 {
+		return false
+	}
+	return strings.Contains(a, b) || strings.Contains(b, a) || distance(a, b) <= maxDescriptionDistance
+}
+
+func normalizeDescription(description string) string {
+	return strings.Join(strings.Fields(strings.ToLower(description)), " ")
+}
+
+// distance is the Levenshtein distance between two strings.
+func distance(a string, b string) int {
+	first, second := []rune(a), []rune(b)
+	previous := make([]int, len(second)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(first); i++ {
+		current := make([]int, len(second)+1)
+		current[0] = i
+		for j := 1; j <= len(second); j++ {
+			cost := 1
+			if first[i-1] == second[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(second)]
+}
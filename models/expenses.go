@@ -14,21 +14,90 @@ type (
 		Description string
 		CreatedAt   time.Time
 		UpdatedAt   *time.Time
+		Category    string
+		Currency    string
+		Kind        Kind
+		RefundOf    int
+		Account     string
+		ToAccount   string
+		PaidBy      string
+		Split       Shares
+		Recurring   bool
+		DeletedAt   *time.Time
 	}
 
 	Expenses []*Expense
 )
 
 const (
-	HeaderFormat         = "|ID    |Description|Amount|Created At|Updated At|"
-	ExpensesStringFormat = "|%-6d|%-11s|%-6d|%-10s|%-10s|\n"
+	HeaderFormat         = "|ID    |Description|Amount|Created At|Updated At|Category  |Currency|Kind    |Net   |"
+	ExpensesStringFormat = "|%-6d|%-11s|%-6s|%-10s|%-10s|%-10s|%-8s|%-8s|%-6s|\n"
+	TrashHeaderFormat    = "|ID    |Description|Amount|Deleted At|"
+	TrashStringFormat    = "|%-6d|%-11s|%-6s|%-10s|\n"
 	DateFormat           = time.DateOnly
 )
 
 func (e *Expense) Print() {
-	if e.UpdatedAt == nil {
-		fmt.Printf(ExpensesStringFormat, e.Id, e.Description, e.Amount, e.CreatedAt.Format(time.DateOnly), "")
-		return
+	e.print(nil)
+}
+
+func (e *Expense) print(refunded map[int]int) {
+	updatedAt := ""
+	if e.UpdatedAt != nil {
+		updatedAt = e.UpdatedAt.Format(DateFormat)
+	}
+	fmt.Printf(ExpensesStringFormat, e.Id, e.Description, FormatAmount(e.Amount), e.CreatedAt.Format(DateFormat), updatedAt, e.Category, e.Currency, e.Kind, FormatAmount(e.Net(refunded)))
+}
+
+func (e Expenses) Print() {
+	e.PrintRefunded(e.Refunded())
+}
+
+// PrintRefunded prints the expenses with their net amounts computed from the
+// given refunds, which may come from entries outside of the printed ones.
+func (e Expenses) PrintRefunded(refunded map[int]int) {
+	fmt.Printf(HeaderFormat + "\n")
+	for _, expense := range e {
+		expense.print(refunded)
+	}
+}
+
+// Apply copies the editable fields of an update, stamping the time of the
+// update.
+func (e *Expense) Apply(update Expense) {
+	e.Amount = update.Amount
+	e.Description = update.Description
+	e.Category = update.Category
+	e.Currency = update.Currency
+	e.Kind = update.TransactionKind()
+	e.RefundOf = update.RefundOf
+	e.Account = update.Account
+	e.ToAccount = update.ToAccount
+	e.PaidBy = update.PaidBy
+	e.Split = update.Split
+	e.Recurring = update.Recurring
+	updatedAt := time.Now()
+	e.UpdatedAt = &updatedAt
+}
+
+// Take removes the expense with the ID and returns it.
+func (e *Expenses) Take(id int) (*Expense, bool) {
+	for i, expense := range *e {
+		if expense.Id == id {
+			*e = append((*e)[:i], (*e)[i+1:]...)
+			return expense, true
+		}
+	}
+	return nil, false
+}
+
+func (e Expenses) PrintTrash() {
+	fmt.Printf(TrashHeaderFormat + "\n")
+	for _, expense := range e {
+		deletedAt := ""
+		if expense.DeletedAt != nil {
+			deletedAt = expense.DeletedAt.Format(DateFormat)
+		}
+		fmt.Printf(TrashStringFormat, expense.Id, expense.Description, FormatAmount(expense.Amount), deletedAt)
 	}
-	fmt.Printf(ExpensesStringFormat, e.Id, e.Description, e.Amount, e.CreatedAt.Format(DateFormat), e.UpdatedAt.Format(DateFormat))
 }
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Forecast struct {
+	Spent            int
+	DailyPace        float64
+	PendingRecurring int
+	Projected        int
+	Budget           int
+	HasBudget        bool
+	Categories       []string
+	DaysLeft         int
+	DailyAllowance   float64
+}
+
+func (e Expenses) InMonth(month time.Month, year int) Expenses {
+	result := Expenses{}
+	for _, expense := range e {
+		if InMonth(expense.SummaryDate(), month, year) {
+			result = append(result, expense)
+		}
+	}
+	return result
+}
+
+// Forecast projects the spending of the month of now. The pace of the
+// non-recurring spending so far is extended to the days left, and recurring
+// expenses of the previous month not yet seen this month are expected again.
+// With budgets, only the spending of the budgeted categories is projected and
+// compared to what they allow, rollovers included.
+func (e Expenses) Forecast(now time.Time, budgets []BudgetStatus) Forecast {
+	budget, categories := 0, []string{}
+	for _, status := range budgets {
+		budget += status.Budget + status.Rollover
+		categories = append(categories, status.Category)
+	}
+	hasBudget := len(budgets) > 0
+	if hasBudget {
+		e = e.inCategories(categories)
+	}
+
+	thisMonth := e.InMonth(now.Month(), now.Year())
+	previous := MonthStart(now.Year(), now.Month()).AddDate(0, -1, 0)
+	lastMonth := e.InMonth(previous.Month(), previous.Year())
+
+	daysInMonth := MonthStart(now.Year(), now.Month()).AddDate(0, 1, -1).Day()
+	elapsed := now.Day()
+
+	forecast := Forecast{
+		Spent:      thisMonth.Spending(),
+		Budget:     budget,
+		HasBudget:  hasBudget,
+		Categories: categories,
+		DaysLeft:   daysInMonth - elapsed + 1,
+	}
+
+	recurringSpent := thisMonth.recurring().Spending()
+	forecast.DailyPace = float64(forecast.Spent-recurringSpent) / float64(elapsed)
+
+	for _, expense := range lastMonth.recurring() {
+		if !thisMonth.recurring().hasDescription(expense.Description) {
+			forecast.PendingRecurring += expense.Amount
+		}
+	}
+
+	forecast.Projected = forecast.Spent + forecast.PendingRecurring + int(forecast.DailyPace*float64(daysInMonth-elapsed))
+
+	if hasBudget {
+		forecast.DailyAllowance = float64(budget-forecast.Spent-forecast.PendingRecurring) / float64(forecast.DaysLeft)
+	}
+
+	return forecast
+}
+
+func (e Expenses) inCategories(categories []string) Expenses {
+	result := Expenses{}
+	for _, expense := range e {
+		for _, category := range categories {
+			if (Query{Category: category}).Matches(expense) {
+				result = append(result, expense)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func (e Expenses) recurring() Expenses {
+	result := Expenses{}
+	for _, expense := range e.OfKind(KindExpense) {
+		if expense.Recurring {
+			result = append(result, expense)
+		}
+	}
+	return result
+}
+
+func (e Expenses) hasDescription(description string) bool {
+	for _, expense := range e {
+		if strings.EqualFold(expense.Description, description) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Forecast) Print() {
+	if f.HasBudget {
+		fmt.Printf("Budgeted categories: %s\n", strings.Join(f.Categories, ", "))
+	}
+	fmt.Printf("Spent so far: %s\n", FormatAmount(f.Spent))
+	fmt.Printf("Daily pace: %s\n", FormatAverage(f.DailyPace))
+	fmt.Printf("Pending recurring: %s\n", FormatAmount(f.PendingRecurring))
+	fmt.Printf("Projected total: %s\n", FormatAmount(f.Projected))
+	if !f.HasBudget {
+		return
+	}
+
+	fmt.Printf("Budget: %s\n", FormatAmount(f.Budget))
+	if f.Projected > f.Budget {
+		fmt.Printf("Projected over budget by %s\n", FormatAmount(f.Projected-f.Budget))
+	} else {
+		fmt.Printf("Projected under budget by %s\n", FormatAmount(f.Budget-f.Projected))
+	}
+	fmt.Printf("Daily allowance left: %s (%d days)\n", FormatAverage(f.DailyAllowance), f.DaysLeft)
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type (
+	Action string
+
+	// Operation is a change made to the expenses, with the state needed to
+	// reverse and replay it.
+	Operation struct {
+		Id        int
+		Action    Action
+		ExpenseId int
+		Before    *Expense
+		After     *Expense
+		At        time.Time
+		Undone    bool
+	}
+
+	Operations []Operation
+
+	// Journal is implemented by stores recording their changes so the last
+	// ones can be undone and redone.
+	Journal interface {
+		Undo(n int) (Operations, error)
+		Redo(n int) (Operations, error)
+		History() Operations
+	}
+)
+
+const (
+	ActionAdd     Action = "add"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	ActionRestore Action = "restore"
+
+	// JournalLimit is the number of operations kept in the journal.
+	JournalLimit = 100
+
+	HistoryHeaderFormat = "|#     |Action |Expense|At                 |Undone|"
+	HistoryStringFormat = "|%-6d|%-7s|%-7d|%-19s|%-6s|\n"
+)
+
+// Inverse is the action reversing the operation.
+func (o Operation) Inverse() Action {
+	switch o.Action {
+	case ActionAdd, ActionRestore:
+		return ActionDelete
+	case ActionDelete:
+		return ActionRestore
+	}
+	return o.Action
+}
+
+func (o Operation) String() string {
+	return fmt.Sprintf("%s of expense %d", o.Action, o.ExpenseId)
+}
+
+func (o Operations) Print() {
+	fmt.Printf(HistoryHeaderFormat + "\n")
+	for _, operation := range o {
+		undone := ""
+		if operation.Undone {
+			undone = "yes"
+		}
+		fmt.Printf(HistoryStringFormat, operation.Id, operation.Action, operation.ExpenseId, operation.At.Format(time.DateTime), undone)
+	}
+}
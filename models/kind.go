@@ -0,0 +1,135 @@
+package models
+
+import "fmt"
+
+type Kind string
+
+const (
+	KindExpense  Kind = "expense"
+	KindIncome   Kind = "income"
+	KindTransfer Kind = "transfer"
+	KindRefund   Kind = "refund"
+)
+
+func ParseKind(value string) (Kind, error) {
+	switch kind := Kind(value); kind {
+	case KindExpense, KindIncome, KindTransfer, KindRefund:
+		return kind, nil
+	}
+	return "", fmt.Errorf("invalid kind %q, expected expense, income, transfer or refund", value)
+}
+
+// TransactionKind returns the kind of the entry, entries recorded before kinds
+// existed being expenses.
+func (e *Expense) TransactionKind() Kind {
+	if e.Kind == "" {
+		return KindExpense
+	}
+	return e.Kind
+}
+
+// Validate checks the entry before it is stored. Amounts are always positive,
+// the kind being what tells money coming in from money going out.
+func (e *Expense) Validate() error {
+	kind := e.TransactionKind()
+	if _, err := ParseKind(string(kind)); err != nil {
+		return err
+	}
+	if e.Amount < 0 {
+		if kind == KindExpense {
+			return fmt.Errorf("amount cannot be negative")
+		}
+		return fmt.Errorf("%s amount cannot be negative", kind)
+	}
+	if kind == KindRefund && e.RefundOf == 0 {
+		return fmt.Errorf("refund must reference an expense")
+	}
+	if kind != KindRefund && e.RefundOf != 0 {
+		return fmt.Errorf("only refunds can reference an expense")
+	}
+	if kind == KindTransfer && (e.Account == "" || e.ToAccount == "") {
+		return fmt.Errorf("transfer requires a from and a to account")
+	}
+	if kind == KindTransfer && e.Account == e.ToAccount {
+		return fmt.Errorf("transfer accounts must be different")
+	}
+	if kind != KindTransfer && e.ToAccount != "" {
+		return fmt.Errorf("only transfers can have a to account")
+	}
+	if len(e.Split) > 0 || e.PaidBy != "" {
+		return e.validateSplit(kind)
+	}
+	return nil
+}
+
+func (e *Expense) validateSplit(kind Kind) error {
+	if kind != KindExpense {
+		return fmt.Errorf("only expenses can be split")
+	}
+	if e.PaidBy == "" || len(e.Split) == 0 {
+		return fmt.Errorf("split expenses require both the split and who paid")
+	}
+	if e.Split.Total() != e.Amount {
+		return fmt.Errorf("split shares add up to %s instead of %s", FormatAmount(e.Split.Total()), FormatAmount(e.Amount))
+	}
+	return nil
+}
+
+func (e Expenses) OfKind(kind Kind) Expenses {
+	result := Expenses{}
+	for _, expense := range e {
+		if expense.TransactionKind() == kind {
+			result = append(result, expense)
+		}
+	}
+	return result
+}
+
+// CashFlow totals entries as they come, for stores that do not hold them
+// all.
+type CashFlow struct {
+	Spending  int
+	Income    int
+	HasIncome bool
+}
+
+// Add accounts an entry: expenses add to the spending, refunds deduct from
+// it and income is kept apart.
+func (c *CashFlow) Add(entry *Expense) {
+	switch entry.TransactionKind() {
+	case KindExpense:
+		c.Spending += entry.Amount
+	case KindRefund:
+		c.Spending -= entry.Amount
+	case KindIncome:
+		c.Income += entry.Amount
+		c.HasIncome = true
+	}
+}
+
+// PrintCashFlow prints the total of the expenses, along with the income and
+// the net balance when there is any income. The currency is appended to the
+// amounts when given.
+func PrintCashFlow(entries Expenses, currency string) {
+	cashFlow := CashFlow{}
+	for _, entry := range entries {
+		cashFlow.Add(entry)
+	}
+	cashFlow.Print(currency)
+}
+
+func (c CashFlow) Print(currency string) {
+	suffix := ""
+	if currency != "" {
+		suffix = " " + currency
+	}
+
+	if !c.HasIncome {
+		fmt.Printf("Total expenses: %s%s\n", FormatAmount(c.Spending), suffix)
+		return
+	}
+
+	fmt.Printf("Total income: %s%s\n", FormatAmount(c.Income), suffix)
+	fmt.Printf("Total expenses: %s%s\n", FormatAmount(c.Spending), suffix)
+	fmt.Printf("Net balance: %s%s\n", FormatAmount(c.Income-c.Spending), suffix)
+}
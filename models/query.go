@@ -0,0 +1,137 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	SortField string
+
+	Query struct {
+		SortBy SortField
+		Desc   bool
+		Limit  int
+		Offset int
+		Tail   int
+
+		Category string
+		Kind     Kind
+		Account  string
+		// From and To bound the summary date of the expenses; To is exclusive
+		// and zero values leave the range open.
+		From time.Time
+		To   time.Time
+	}
+)
+
+const (
+	SortById          SortField = "id"
+	SortByAmount      SortField = "amount"
+	SortByDate        SortField = "date"
+	SortByDescription SortField = "description"
+)
+
+func ParseSortField(value string) (SortField, error) {
+	switch field := SortField(value); field {
+	case "", SortById, SortByAmount, SortByDate, SortByDescription:
+		return field, nil
+	}
+	return "", fmt.Errorf("invalid sort field %q", value)
+}
+
+func (q Query) Validate() error {
+	if _, err := ParseSortField(string(q.SortBy)); err != nil {
+		return err
+	}
+	if q.Limit < 0 || q.Offset < 0 || q.Tail < 0 {
+		return fmt.Errorf("limit, offset and tail cannot be negative")
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return fmt.Errorf("from must be before to")
+	}
+	return nil
+}
+
+func (q Query) Matches(expense *Expense) bool {
+	if q.Category != "" && !strings.EqualFold(q.Category, expense.Category) {
+		return false
+	}
+	if q.Kind != "" && q.Kind != expense.TransactionKind() {
+		return false
+	}
+	if q.Account != "" && q.Account != expense.Account && q.Account != expense.ToAccount {
+		return false
+	}
+	date := expense.SummaryDate()
+	if !q.From.IsZero() && date.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !date.Before(q.To) {
+		return false
+	}
+	return true
+}
+
+func (q Query) ForMonth(month time.Month, year int) Query {
+	q.From = time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	q.To = q.From.AddDate(0, 1, 0)
+	return q
+}
+
+// Find returns the expenses matching the query. The receiver is never
+// reordered: matching expenses are copied, the most recent ones kept by tail,
+// then sorted, and offset and limit are applied in that order.
+func (e Expenses) Find(query Query) Expenses {
+	result := Expenses{}
+	for _, expense := range e {
+		if query.Matches(expense) {
+			result = append(result, expense)
+		}
+	}
+
+	if query.Tail > 0 && query.Tail < len(result) {
+		sort.SliceStable(result, func(i, j int) bool {
+			return result[i].less(result[j], SortByDate)
+		})
+		result = result[len(result)-query.Tail:]
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if query.Desc {
+			return result[j].less(result[i], query.SortBy)
+		}
+		return result[i].less(result[j], query.SortBy)
+	})
+
+	if query.Offset >= len(result) {
+		return Expenses{}
+	}
+	result = result[query.Offset:]
+
+	if query.Limit > 0 && query.Limit < len(result) {
+		result = result[:query.Limit]
+	}
+
+	return result
+}
+
+func (e *Expense) less(other *Expense, field SortField) bool {
+	switch field {
+	case SortByAmount:
+		if e.Amount != other.Amount {
+			return e.Amount < other.Amount
+		}
+	case SortByDate:
+		if !e.CreatedAt.Equal(other.CreatedAt) {
+			return e.CreatedAt.Before(other.CreatedAt)
+		}
+	case SortByDescription:
+		if e.Description != other.Description {
+			return e.Description < other.Description
+		}
+	}
+	return e.Id < other.Id
+}
@@ -0,0 +1,76 @@
+package models
+
+import "fmt"
+
+// Refunded returns the total refunded per original expense ID.
+func (e Expenses) Refunded() map[int]int {
+	refunded := map[int]int{}
+	for _, expense := range e {
+		if expense.TransactionKind() == KindRefund {
+			refunded[expense.RefundOf] += expense.Amount
+		}
+	}
+	return refunded
+}
+
+// Net is the amount of the entry once its refunds are deducted. Refunds are
+// credits and count negatively.
+func (e *Expense) Net(refunded map[int]int) int {
+	if e.TransactionKind() == KindRefund {
+		return -e.Amount
+	}
+	return e.Amount - refunded[e.Id]
+}
+
+// Spent is what the entry adds to the spending: the amount of an expense,
+// minus the amount of a refund and nothing for the other kinds.
+func (e *Expense) Spent() int {
+	switch e.TransactionKind() {
+	case KindExpense:
+		return e.Amount
+	case KindRefund:
+		return -e.Amount
+	}
+	return 0
+}
+
+// spendingEntries keeps the expenses and the refunds, the entries the
+// spending is made of.
+func (e Expenses) spendingEntries() Expenses {
+	return append(e.OfKind(KindExpense), e.OfKind(KindRefund)...)
+}
+
+// Spending is the total of the expenses minus the refunds, each accounted on
+// its own date.
+func (e Expenses) Spending() int {
+	return e.OfKind(KindExpense).Aggregate().Total - e.OfKind(KindRefund).Aggregate().Total
+}
+
+// ValidateRefund checks a refund against the entries already stored: it must
+// reference an existing expense and not refund more than what is left of it.
+func (e Expenses) ValidateRefund(refund *Expense) error {
+	var original *Expense
+	refunded := 0
+	for _, expense := range e {
+		if expense.Id == refund.RefundOf {
+			original = expense
+		}
+		if expense.TransactionKind() == KindRefund && expense.RefundOf == refund.RefundOf && expense.Id != refund.Id {
+			refunded += expense.Amount
+		}
+	}
+
+	if refund.Amount <= 0 {
+		return fmt.Errorf("refund amount must be positive")
+	}
+	if original == nil {
+		return fmt.Errorf("expense with ID %d not found", refund.RefundOf)
+	}
+	if original.TransactionKind() != KindExpense {
+		return fmt.Errorf("only expenses can be refunded")
+	}
+	if refund.Amount > original.Amount-refunded {
+		return fmt.Errorf("refund exceeds the %s left to refund of expense %d", FormatAmount(original.Amount-refunded), original.Id)
+	}
+	return nil
+}
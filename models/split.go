@@ -0,0 +1,287 @@
+package models
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	Share struct {
+		Person string
+		Amount int
+	}
+
+	Shares []Share
+
+	PersonBalance struct {
+		Person  string
+		Balance int
+	}
+
+	Settlement struct {
+		From   string
+		To     string
+		Amount int
+	}
+)
+
+// ParseSplit resolves a split of the amount between people. Shares are equal
+// for "alice,bob,me", percentages for "alice:50%,bob:50%" and exact amounts
+// for "alice:12,bob:8"; the styles cannot be mixed. Remainders of equal and
+// percentage splits go to the first people listed.
+func ParseSplit(value string, amount int) (Shares, error) {
+	parts := strings.Split(value, ",")
+	shares := make(Shares, 0, len(parts))
+	weights := make([]int, 0, len(parts))
+	style := ""
+
+	for _, part := range parts {
+		person, weight, found := strings.Cut(strings.TrimSpace(part), ":")
+		if person == "" {
+			return nil, fmt.Errorf("invalid split %q, expected a person in every share", value)
+		}
+
+		partStyle := "equal"
+		if found {
+			partStyle = "exact"
+			if strings.HasSuffix(weight, "%") {
+				partStyle = "percentage"
+				weight = strings.TrimSuffix(weight, "%")
+			}
+		}
+		if style != "" && style != partStyle {
+			return nil, fmt.Errorf("invalid split %q, equal, percentage and exact shares cannot be mixed", value)
+		}
+		style = partStyle
+
+		// exact shares are amounts, the others whole weights
+		number := 1
+		if found {
+			parse := strconv.Atoi
+			if partStyle == "exact" {
+				parse = ParseAmount
+			}
+			parsed, err := parse(weight)
+			if err != nil || parsed < 0 {
+				return nil, fmt.Errorf("invalid share %q", part)
+			}
+			number = parsed
+		}
+
+		for _, share := range shares {
+			if share.Person == person {
+				return nil, fmt.Errorf("%s appears more than once in the split", person)
+			}
+		}
+		shares = append(shares, Share{Person: person})
+		weights = append(weights, number)
+	}
+
+	switch style {
+	case "exact":
+		for i := range shares {
+			shares[i].Amount = weights[i]
+		}
+	case "percentage":
+		if sum(weights) != 100 {
+			return nil, fmt.Errorf("split percentages must add up to 100")
+		}
+		distribute(shares, weights, amount)
+	default:
+		distribute(shares, weights, amount)
+	}
+
+	if shares.Total() != amount {
+		return nil, fmt.Errorf("split shares add up to %s instead of %s", FormatAmount(shares.Total()), FormatAmount(amount))
+	}
+	return shares, nil
+}
+
+func distribute(shares Shares, weights []int, amount int) {
+	total := sum(weights)
+	remainder := amount
+	for i := range shares {
+		shares[i].Amount = amount * weights[i] / total
+		remainder -= shares[i].Amount
+	}
+	for i := 0; remainder > 0; i = (i + 1) % len(shares) {
+		shares[i].Amount++
+		remainder--
+	}
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
+
+func (s Shares) Total() int {
+	total := 0
+	for _, share := range s {
+		total += share.Amount
+	}
+	return total
+}
+
+func (s Shares) String() string {
+	parts := make([]string, 0, len(s))
+	for _, share := range s {
+		parts = append(parts, share.Person+":"+FormatAmount(share.Amount))
+	}
+	return strings.Join(parts, ",")
+}
+
+// PersonBalances returns what every person is owed (positive) or owes
+// (negative) from the split expenses: the payer is credited the amount and
+// every person is debited their share.
+func (e Expenses) PersonBalances() []PersonBalance {
+	balances := map[string]int{}
+	for _, expense := range e {
+		if len(expense.Split) == 0 {
+			continue
+		}
+		balances[expense.PaidBy] += expense.Amount
+		for _, share := range expense.Split {
+			balances[share.Person] -= share.Amount
+		}
+	}
+
+	result := make([]PersonBalance, 0, len(balances))
+	for person, balance := range balances {
+		result = append(result, PersonBalance{Person: person, Balance: balance})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Person < result[j].Person
+	})
+	return result
+}
+
+// maxExactSettlement is the number of people owing or owed up to which
+// Settle looks for the fewest transfers, the search growing threefold with
+// every person. Larger groups are settled in one go.
+const maxExactSettlement = 16
+
+// Settle returns the fewest transfers that zero out the balances. The people
+// are split into as many groups whose balances add up to zero as possible,
+// each settled on its own by repeatedly paying the largest creditor from the
+// largest debtor, which needs one transfer less than the people of the group.
+func Settle(balances []PersonBalance) []Settlement {
+	owing := []PersonBalance{}
+	for _, balance := range balances {
+		if balance.Balance != 0 {
+			owing = append(owing, balance)
+		}
+	}
+
+	settlements := []Settlement{}
+	for _, group := range zeroSumGroups(owing) {
+		settlements = append(settlements, settleGroup(group)...)
+	}
+	return settlements
+}
+
+// zeroSumGroups splits the balances into the most groups adding up to zero,
+// searching the subsets of people, each a bit of a mask.
+func zeroSumGroups(balances []PersonBalance) [][]PersonBalance {
+	people := len(balances)
+	if people == 0 || people > maxExactSettlement {
+		return [][]PersonBalance{balances}
+	}
+
+	all := 1<<people - 1
+	sums := make([]int, all+1)
+	for mask := 1; mask <= all; mask++ {
+		sums[mask] = sums[mask&(mask-1)] + balances[bits.TrailingZeros(uint(mask))].Balance
+	}
+	if sums[all] != 0 {
+		return [][]PersonBalance{balances}
+	}
+
+	// groups is the most groups a mask adding up to zero splits into, first
+	// the one of them holding its first person
+	groups := make([]int, all+1)
+	first := make([]int, all+1)
+	for mask := 1; mask <= all; mask++ {
+		if sums[mask] != 0 {
+			continue
+		}
+		lowest := mask & -mask
+		rest := mask ^ lowest
+		for others := rest; ; others = (others - 1) & rest {
+			group := others | lowest
+			if sums[group] == 0 && groups[mask^group]+1 > groups[mask] {
+				groups[mask] = groups[mask^group] + 1
+				first[mask] = group
+			}
+			if others == 0 {
+				break
+			}
+		}
+	}
+
+	split := [][]PersonBalance{}
+	for mask := all; mask != 0; mask ^= first[mask] {
+		group := []PersonBalance{}
+		for person := range people {
+			if first[mask]&(1<<person) != 0 {
+				group = append(group, balances[person])
+			}
+		}
+		split = append(split, group)
+	}
+	return split
+}
+
+func settleGroup(balances []PersonBalance) []Settlement {
+	creditors, debtors := []PersonBalance{}, []PersonBalance{}
+	for _, balance := range balances {
+		if balance.Balance > 0 {
+			creditors = append(creditors, balance)
+		}
+		if balance.Balance < 0 {
+			debtors = append(debtors, PersonBalance{Person: balance.Person, Balance: -balance.Balance})
+		}
+	}
+
+	settlements := []Settlement{}
+	for len(creditors) > 0 && len(debtors) > 0 {
+		sortByBalance(creditors)
+		sortByBalance(debtors)
+
+		amount := min(creditors[0].Balance, debtors[0].Balance)
+		settlements = append(settlements, Settlement{From: debtors[0].Person, To: creditors[0].Person, Amount: amount})
+
+		creditors[0].Balance -= amount
+		debtors[0].Balance -= amount
+		if creditors[0].Balance == 0 {
+			creditors = creditors[1:]
+		}
+		if debtors[0].Balance == 0 {
+			debtors = debtors[1:]
+		}
+	}
+	return settlements
+}
+
+func sortByBalance(balances []PersonBalance) {
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].Balance != balances[j].Balance {
+			return balances[i].Balance > balances[j].Balance
+		}
+		return balances[i].Person < balances[j].Person
+	})
+}
+
+func (b PersonBalance) Print() {
+	fmt.Printf("%s: %s\n", b.Person, formatDelta(b.Balance))
+}
+
+func (s Settlement) Print() {
+	fmt.Printf("%s pays %s %s\n", s.From, s.To, FormatAmount(s.Amount))
+}
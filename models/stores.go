@@ -6,9 +6,95 @@ type (
 	Store interface {
 		Add(expense Expense) error
 		List()
+		ListBy(query Query)
+		Find(query Query) Expenses
+		Get(id int) (*Expense, error)
+		// LastId is the ID given to the latest expense added. IDs are
+		// never reused, so it is also the highest ever given.
+		LastId() int
 		Update(expense Expense) error
 		Delete(id int) error
+		Trashed() Expenses
+		Restore(id int) error
+		EmptyTrash(deletedBefore time.Time) (int, error)
 		Summary()
 		SummaryForMonth(month time.Month)
+		SummaryGroupedBy(groupBy GroupBy)
+	}
+
+	// Wrapper is implemented by stores adding behaviour to another store.
+	Wrapper interface {
+		Unwrap() Store
+	}
+
+	// Verifier is implemented by stores able to prove their file was not
+	// altered, returning the number of entries verified. Head is the hash the
+	// verified entries end on, which changes with every change of the store.
+	Verifier interface {
+		Verify() (int, error)
+		Head() string
+	}
+
+	// Codec transforms the content of the files of the stores, to encrypt
+	// them at rest. Encoded tells whether content was encoded by the codec.
+	Codec interface {
+		Encode(plain []byte) ([]byte, error)
+		Decode(encoded []byte) ([]byte, error)
+		Encoded(content []byte) bool
+	}
+
+	// Encrypter is implemented by stores whose files can be converted to and
+	// from their encrypted form.
+	Encrypter interface {
+		Encrypt() error
+		Decrypt() error
+	}
+
+	// Compactor is implemented by stores able to shrink their files.
+	Compactor interface {
+		Compact() error
+	}
+
+	// Scanner is implemented by stores able to look for an expense without
+	// holding all of those matching the query, stopping at the first one
+	// satisfying found.
+	Scanner interface {
+		Any(query Query, found func(expense *Expense) bool) bool
+	}
+
+	// Exporter writes expenses to a file in the format its name asks for.
+	Exporter interface {
+		Export(filename string, expenses Expenses) error
 	}
 )
+
+// As finds the first store of the chain of wrapped stores implementing T,
+// unwrapping them one at a time so the stores past it are never opened.
+func As[T any](store Store) (T, bool) {
+	for store != nil {
+		if found, ok := store.(T); ok {
+			return found, true
+		}
+		wrapper, ok := store.(Wrapper)
+		if !ok {
+			break
+		}
+		store = wrapper.Unwrap()
+	}
+	var none T
+	return none, false
+}
+
+// Chain lists a store followed by the stores it wraps.
+func Chain(store Store) []Store {
+	chain := []Store{}
+	for store != nil {
+		chain = append(chain, store)
+		wrapper, ok := store.(Wrapper)
+		if !ok {
+			break
+		}
+		store = wrapper.Unwrap()
+	}
+	return chain
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+type (
+	GroupBy string
+
+	PeriodSummary struct {
+		Period      string
+		Count       int
+		Total       int
+		Average     float64
+		Delta       int
+		HasPrevious bool
+		Income      int
+		Net         int
+		order       string
+	}
+)
+
+const (
+	GroupByMonth   GroupBy = "month"
+	GroupByWeek    GroupBy = "week"
+	GroupByYear    GroupBy = "year"
+	GroupByWeekday GroupBy = "weekday"
+)
+
+const (
+	SummaryHeaderFormat = "|Period    |Count |Total |Average |Delta  |Income|Net   |"
+	SummaryStringFormat = "|%-10s|%-6d|%-6s|%-8s|%-7s|%-6s|%-6s|\n"
+)
+
+func ParseGroupBy(value string) (GroupBy, error) {
+	switch groupBy := GroupBy(value); groupBy {
+	case GroupByMonth, GroupByWeek, GroupByYear, GroupByWeekday:
+		return groupBy, nil
+	}
+	return "", fmt.Errorf("invalid group by %q, expected month, week, year or weekday", value)
+}
+
+// SummaryDate is the date an expense is accounted on in summaries: the last
+// update when there is one, the creation date otherwise.
+func (e *Expense) SummaryDate() time.Time {
+	if e.UpdatedAt == nil {
+		return e.CreatedAt
+	}
+	return *e.UpdatedAt
+}
+
+// InMonth tells whether a date falls in the month of the year, as the monthly
+// summary buckets expenses.
+func InMonth(date time.Time, month time.Month, year int) bool {
+	return date.Month() == month && date.Year() == year
+}
+
+func (e Expenses) GroupBy(groupBy GroupBy) []PeriodSummary {
+	groups := map[string]Expenses{}
+	orders := map[string]string{}
+	for _, expense := range e {
+		period, order := periodOf(expense.SummaryDate(), groupBy)
+		groups[period] = append(groups[period], expense)
+		orders[period] = order
+	}
+
+	summaries := make([]PeriodSummary, 0, len(groups))
+	for period, entries := range groups {
+		count := len(entries.OfKind(KindExpense))
+		total := entries.Spending()
+		income := entries.OfKind(KindIncome).Aggregate().Total
+		average := 0.0
+		if count > 0 {
+			average = float64(total) / float64(count)
+		}
+		summaries = append(summaries, PeriodSummary{
+			Period:  period,
+			Count:   count,
+			Total:   total,
+			Average: average,
+			Income:  income,
+			Net:     income - total,
+			order:   orders[period],
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].order < summaries[j].order
+	})
+
+	for i := 1; i < len(summaries); i++ {
+		summaries[i].Delta = summaries[i].Total - summaries[i-1].Total
+		summaries[i].HasPrevious = true
+	}
+
+	return summaries
+}
+
+func periodOf(date time.Time, groupBy GroupBy) (period string, order string) {
+	switch groupBy {
+	case GroupByWeek:
+		year, week := date.ISOWeek()
+		period = fmt.Sprintf("%d-W%02d", year, week)
+		return period, period
+	case GroupByYear:
+		period = fmt.Sprintf("%d", date.Year())
+		return period, period
+	case GroupByWeekday:
+		return date.Weekday().String(), fmt.Sprintf("%d", date.Weekday())
+	default:
+		period = date.Format("2006-01")
+		return period, period
+	}
+}
+
+func (p PeriodSummary) Print() {
+	delta := ""
+	if p.HasPrevious {
+		delta = formatDelta(p.Delta)
+	}
+	fmt.Printf(SummaryStringFormat, p.Period, p.Count, FormatAmount(p.Total), FormatAverage(p.Average), delta, FormatAmount(p.Income), FormatAmount(p.Net))
+}
+
+func PrintPeriodSummaries(summaries []PeriodSummary) {
+	fmt.Printf(SummaryHeaderFormat + "\n")
+	for _, summary := range summaries {
+		summary.Print()
+	}
+}
@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccount(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("✅ should compute the balance of the accounts from their opening balance", func(t *testing.T) {
+		// Given
+		accounts := models.Accounts{{Name: "checking", OpeningBalance: 1000}, {Name: "visa"}}
+		entries := models.Expenses{
+			{Id: 1, Amount: 3000, Kind: models.KindIncome, Account: "checking"},
+			{Id: 2, Amount: 40, Kind: models.KindExpense, Account: "visa"},
+			{Id: 3, Amount: 10, Kind: models.KindRefund, RefundOf: 2, Account: "visa"},
+			{Id: 4, Amount: 30, Kind: models.KindTransfer, Account: "checking", ToAccount: "visa"},
+			{Id: 5, Amount: 5, Kind: models.KindExpense, Account: "cash"},
+			{Id: 6, Amount: 7, Kind: models.KindExpense},
+		}
+
+		// When
+		balances := accounts.Balances(entries)
+
+		// Then
+		asserts.Equal([]models.AccountBalance{
+			{Account: models.Account{Name: "cash"}, Balance: -5},
+			{Account: models.Account{Name: "checking", OpeningBalance: 1000}, Balance: 3970},
+			{Account: models.Account{Name: "visa"}, Balance: 0},
+		}, balances)
+	})
+
+	t.Run("❌ should not validate a transfer without both accounts", func(t *testing.T) {
+		// Given
+		transfer := models.Expense{Amount: 30, Kind: models.KindTransfer, Account: "checking"}
+
+		// When
+		err := transfer.Validate()
+
+		// Then
+		asserts.Equal("transfer requires a from and a to account", err.Error())
+	})
+
+	t.Run("❌ should not validate a transfer to the same account", func(t *testing.T) {
+		// Given
+		transfer := models.Expense{Amount: 30, Kind: models.KindTransfer, Account: "visa", ToAccount: "visa"}
+
+		// When
+		err := transfer.Validate()
+
+		// Then
+		asserts.Equal("transfer accounts must be different", err.Error())
+	})
+}
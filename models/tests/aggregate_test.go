@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregate(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("✅ should aggregate the amounts of the expenses", func(t *testing.T) {
+		// Given
+		expenses := models.Expenses{
+			{Id: 1, Amount: 10},
+			{Id: 2, Amount: 40},
+			{Id: 3, Amount: 20},
+			{Id: 4, Amount: 30},
+		}
+
+		// When
+		aggregation := expenses.Aggregate()
+
+		// Then
+		asserts.Equal(4, aggregation.Count)
+		asserts.Equal(100, aggregation.Total)
+		asserts.Equal(10, aggregation.Min)
+		asserts.Equal(40, aggregation.Max)
+		asserts.Equal(25.0, aggregation.Mean)
+		asserts.Equal(25.0, aggregation.Median)
+		asserts.InDelta(37.0, aggregation.P90, 0.001)
+		asserts.InDelta(11.180, aggregation.StdDev, 0.001)
+	})
+
+	t.Run("✅ should return an empty aggregation when there are no expenses", func(t *testing.T) {
+		// When
+		aggregation := models.Expenses{}.Aggregate()
+
+		// Then
+		asserts.Equal(models.Aggregation{}, aggregation)
+	})
+
+	t.Run("✅ should return the largest expenses first", func(t *testing.T) {
+		// Given
+		expenses := models.Expenses{
+			{Id: 1, Amount: 10},
+			{Id: 2, Amount: 40},
+			{Id: 3, Amount: 20},
+		}
+
+		// When
+		largest := expenses.Largest(2)
+
+		// Then
+		asserts.Equal(2, len(largest))
+		asserts.Equal(2, largest[0].Id)
+		asserts.Equal(3, largest[1].Id)
+	})
+}
@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"encoding/json"
+	"expense-tracker/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAmount(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("✅ should parse amounts with up to two decimals into hundredths", func(t *testing.T) {
+		for value, expected := range map[string]int{"20": 2000, "12.5": 1250, "12.50": 1250, "0.05": 5, "-3.20": -320, "92233720368547757.99": 9223372036854775799} {
+			// When
+			amount, err := models.ParseAmount(value)
+
+			// Then
+			asserts.Nil(err)
+			asserts.Equal(expected, amount, value)
+		}
+	})
+
+	t.Run("❌ should not parse amounts with more than two decimals, no digits or too large", func(t *testing.T) {
+		for _, value := range []string{"12.505", "12.", ".5", "abc", "1.-5", "", "99999999999999999", "-99999999999999999"} {
+			// When
+			_, err := models.ParseAmount(value)
+
+			// Then
+			asserts.NotNil(err, value)
+		}
+	})
+
+	t.Run("✅ should format whole amounts without decimals", func(t *testing.T) {
+		asserts.Equal("20", models.FormatAmount(2000))
+		asserts.Equal("12.50", models.FormatAmount(1250))
+		asserts.Equal("-0.05", models.FormatAmount(-5))
+	})
+
+	t.Run("✅ should write amounts in JSON as units and read legacy ones", func(t *testing.T) {
+		// Given
+		expense := models.Expense{Id: 1, Amount: 1250, Description: "Lunch", Split: models.Shares{{Person: "alice", Amount: 1250}}}
+
+		// When
+		content, err := json.Marshal(expense)
+		decoded := models.Expense{}
+		decodeErr := json.Unmarshal(content, &decoded)
+		legacy := models.Expense{}
+		legacyErr := json.Unmarshal([]byte(`{"Id":2,"Amount":20,"Description":"Coffee"}`), &legacy)
+
+		// Then
+		asserts.Nil(err)
+		asserts.Contains(string(content), `"Amount":12.50`)
+		asserts.Nil(decodeErr)
+		asserts.Equal(expense.Amount, decoded.Amount)
+		asserts.Equal(expense.Split, decoded.Split)
+		asserts.Equal("Lunch", decoded.Description)
+		asserts.Nil(legacyErr)
+		asserts.Equal(2000, legacy.Amount)
+	})
+}
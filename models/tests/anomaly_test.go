@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnomaly(t *testing.T) {
+	asserts := assert.New(t)
+
+	lunches := func() models.Expenses {
+		return models.Expenses{
+			{Id: 1, Amount: 20, Category: "food"},
+			{Id: 2, Amount: 22, Category: "food"},
+			{Id: 3, Amount: 18, Category: "food"},
+			{Id: 4, Amount: 21, Category: "food"},
+			{Id: 5, Amount: 300, Description: "Rent"},
+		}
+	}
+
+	t.Run("✅ should flag an expense much larger than the others of its category", func(t *testing.T) {
+		// Given
+		expenses := append(lunches(), &models.Expense{Id: 6, Amount: 2000, Category: "Food"})
+
+		// When
+		anomalies := expenses.Anomalies(models.DefaultAnomalyThreshold)
+
+		// Then
+		asserts.Equal(1, len(anomalies))
+		asserts.Equal(6, anomalies[0].Expense.Id)
+		asserts.Equal("food", anomalies[0].Group)
+		asserts.Equal(20.5, anomalies[0].Median)
+	})
+
+	t.Run("✅ should not flag usual amounts nor groups without enough history", func(t *testing.T) {
+		// Given
+		expenses := append(lunches(), &models.Expense{Id: 6, Amount: 25, Category: "food"})
+
+		// When
+		anomalies := expenses.Anomalies(models.DefaultAnomalyThreshold)
+
+		// Then
+		asserts.Equal(0, len(anomalies))
+	})
+
+	t.Run("✅ should check a new expense when all the previous amounts are equal", func(t *testing.T) {
+		// Given
+		expenses := models.Expenses{
+			{Id: 1, Amount: 20, Description: "Lunch"},
+			{Id: 2, Amount: 20, Description: "Lunch"},
+			{Id: 3, Amount: 20, Description: "lunch"},
+		}
+
+		// When
+		anomaly, ok := expenses.Check(&models.Expense{Amount: 200, Description: "Lunch"}, models.DefaultAnomalyThreshold)
+
+		// Then
+		asserts.True(ok)
+		asserts.True(math.IsInf(anomaly.Score, 1))
+	})
+	t.Run("✅ should only compare an expense with those of its currency", func(t *testing.T) {
+		// Given
+		expenses := models.Expenses{
+			{Id: 1, Amount: 2000, Description: "Lunch", Currency: "USD"},
+			{Id: 2, Amount: 2100, Description: "Lunch", Currency: "USD"},
+			{Id: 3, Amount: 1900, Description: "Lunch", Currency: "USD"},
+		}
+
+		// When
+		_, ok := expenses.Check(&models.Expense{Amount: 150000, Description: "Lunch", Currency: "JPY"}, models.DefaultAnomalyThreshold)
+
+		// Then
+		asserts.False(ok)
+	})
+}
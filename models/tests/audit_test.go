@@ -0,0 +1,38 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudit(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("✅ should list the fields changed by a revision", func(t *testing.T) {
+		// Given
+		before := &models.Expense{Id: 1, Amount: 2000, Description: "Lunch", Category: "food"}
+		after := &models.Expense{Id: 1, Amount: 2500, Description: "Lunch", Category: "food", Account: "visa"}
+
+		// When
+		changes := models.Diff(before, after)
+
+		// Then
+		asserts.Equal([]models.Change{
+			{Field: "amount", From: "20", To: "25"},
+			{Field: "account", From: "", To: "visa"},
+		}, changes)
+	})
+
+	t.Run("✅ should list the fields set on a new expense", func(t *testing.T) {
+		// When
+		changes := models.Diff(nil, &models.Expense{Id: 1, Amount: 2000, Description: "Lunch"})
+
+		// Then
+		asserts.Equal([]models.Change{
+			{Field: "description", From: "", To: "Lunch"},
+			{Field: "amount", From: "", To: "20"},
+		}, changes)
+	})
+}
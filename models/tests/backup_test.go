@@ -0,0 +1,34 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackup(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("✅ should list the expenses added, removed and changed", func(t *testing.T) {
+		// Given
+		current := models.Expenses{
+			{Id: 1, Amount: 2000, Description: "Lunch"},
+			{Id: 2, Amount: 1000, Description: "Coffee"},
+		}
+		backup := models.Expenses{
+			{Id: 1, Amount: 2500, Description: "Lunch"},
+			{Id: 3, Amount: 3000, Description: "Taxi"},
+		}
+
+		// When
+		diffs := models.DiffExpenses(current, backup)
+
+		// Then
+		asserts.Equal([]models.ExpenseDiff{
+			{Id: 1, Status: models.DiffChanged, Changes: []models.Change{{Field: "amount", From: "20", To: "25"}}},
+			{Id: 3, Status: models.DiffAdded},
+			{Id: 2, Status: models.DiffRemoved},
+		}, diffs)
+	})
+}
@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudget(t *testing.T) {
+	asserts := assert.New(t)
+
+	july := models.MonthStart(2024, time.July)
+	august := models.MonthStart(2024, time.August)
+	september := models.MonthStart(2024, time.September)
+
+	t.Run("✅ should compute the spent, remaining and percent of each category", func(t *testing.T) {
+		// Given
+		budgets := models.Budgets{{Category: "food", Amount: 400, From: july}, {Category: "fun", Amount: 10, From: july}}
+		expenses := models.Expenses{
+			{Id: 1, Amount: 300, Category: "food", CreatedAt: august},
+			{Id: 2, Amount: 15, Category: "fun", CreatedAt: august},
+			{Id: 3, Amount: 50, Category: "food", CreatedAt: july},
+		}
+
+		// When
+		statuses := budgets.Status(expenses, august)
+
+		// Then
+		asserts.Equal([]models.BudgetStatus{
+			{Category: "food", Budget: 400, Spent: 300, Remaining: 100, Percent: 75},
+			{Category: "fun", Budget: 10, Spent: 15, Remaining: -5, Percent: 150},
+		}, statuses)
+	})
+
+	t.Run("✅ should roll over what is left unspent to the next months", func(t *testing.T) {
+		// Given
+		budgets := models.Budgets{{Category: "food", Amount: 400, Rollover: true, From: july}}
+		expenses := models.Expenses{
+			{Id: 1, Amount: 300, Category: "food", CreatedAt: july},
+			{Id: 2, Amount: 450, Category: "food", CreatedAt: august},
+		}
+
+		// When
+		statuses := budgets.Status(expenses, september)
+
+		// Then
+		asserts.Equal(50, statuses[0].Rollover)
+		asserts.Equal(450, statuses[0].Remaining)
+	})
+
+	t.Run("✅ should use the latest budget effective in the month", func(t *testing.T) {
+		// Given
+		budgets := models.Budgets{{Category: "food", Amount: 400, From: july}, {Category: "Food", Amount: 300, From: september}}
+
+		// When
+		budget, ok := budgets.For("food", august)
+
+		// Then
+		asserts.True(ok)
+		asserts.Equal(400, budget.Amount)
+	})
+}
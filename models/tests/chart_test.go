@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChart(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("✅ should render bars proportional to the largest value within the width", func(t *testing.T) {
+		// Given
+		bars := []models.Bar{{Label: "food", Value: 4000}, {Label: "bus", Value: 1000}}
+
+		// When
+		result := models.RenderBarChart(bars, 15)
+
+		// Then
+		asserts.Equal("food ██████ 40\nbus  █▌     10\n", result)
+	})
+
+	t.Run("✅ should render a sparkline of the daily totals including empty days", func(t *testing.T) {
+		// Given
+		expenses := models.Expenses{
+			{Id: 1, Amount: 70, CreatedAt: time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)},
+			{Id: 2, Amount: 10, CreatedAt: time.Date(2024, time.August, 3, 0, 0, 0, 0, time.UTC)},
+			{Id: 3, Amount: 20, CreatedAt: time.Date(2024, time.August, 3, 0, 0, 0, 0, time.UTC)},
+		}
+
+		// When
+		totals := expenses.DailyTotals()
+
+		// Then
+		asserts.Equal([]int{70, 0, 30}, totals)
+		asserts.Equal("█▁▄", models.Sparkline(totals, 10))
+		asserts.Equal("▁█", models.Sparkline(totals, 2))
+	})
+
+	t.Run("✅ should group uncategorized expenses in the category bars", func(t *testing.T) {
+		// Given
+		expenses := models.Expenses{
+			{Id: 1, Amount: 5},
+			{Id: 2, Amount: 20, Category: "food"},
+		}
+
+		// When
+		bars := expenses.CategoryBars()
+
+		// Then
+		asserts.Equal([]models.Bar{{Label: "food", Value: 20}, {Label: models.Uncategorized, Value: 5}}, bars)
+	})
+
+	t.Run("✅ should deduct refunds and leave out income like the summary", func(t *testing.T) {
+		// Given
+		expenses := models.Expenses{
+			{Id: 1, Amount: 40, Category: "food", CreatedAt: time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)},
+			{Id: 2, Amount: 15, Category: "food", Kind: models.KindRefund, RefundOf: 1, CreatedAt: time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC)},
+			{Id: 3, Amount: 3000, Category: "salary", Kind: models.KindIncome, CreatedAt: time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC)},
+		}
+
+		// When
+		bars := expenses.CategoryBars()
+		totals := expenses.DailyTotals()
+
+		// Then
+		asserts.Equal([]models.Bar{{Label: "food", Value: 25}}, bars)
+		asserts.Equal([]int{40, -15}, totals)
+		asserts.Equal(expenses.Spending(), bars[0].Value)
+	})
+}
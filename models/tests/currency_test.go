@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrency(t *testing.T) {
+	asserts := assert.New(t)
+
+	august := time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)
+	september := time.Date(2024, time.September, 1, 0, 0, 0, 0, time.UTC)
+	rates := models.Rates{
+		{From: "EUR", To: "USD", Rate: 1.09, Date: august},
+		{From: "EUR", To: "USD", Rate: 1.11, Date: september},
+		{From: "USD", To: "GBP", Rate: 0.8, Date: august},
+	}
+
+	t.Run("✅ should look up the rate effective on the date", func(t *testing.T) {
+		// When
+		rate, err := rates.Lookup("EUR", "USD", time.Date(2024, time.August, 20, 0, 0, 0, 0, time.UTC))
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(1.09, rate.Rate)
+		asserts.Equal(august, rate.Date)
+	})
+
+	t.Run("✅ should use the inverse rate when only the opposite direction is known", func(t *testing.T) {
+		// When
+		rate, err := rates.Lookup("GBP", "USD", september)
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(1.25, rate.Rate)
+	})
+
+	t.Run("❌ should not look up a rate before any is effective", func(t *testing.T) {
+		// When
+		_, err := rates.Lookup("EUR", "USD", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC))
+
+		// Then
+		asserts.Equal("no rate from EUR to USD on 2024-07-01", err.Error())
+	})
+
+	t.Run("✅ should convert the expenses to the base currency", func(t *testing.T) {
+		// Given
+		expenses := models.Expenses{
+			{Id: 1, Amount: 100, Currency: "EUR", CreatedAt: september},
+			{Id: 2, Amount: 20, CreatedAt: september},
+		}
+
+		// When
+		converted, used, err := rates.Convert(expenses, "USD", "USD")
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(111, converted[0].Amount)
+		asserts.Equal("USD", converted[0].Currency)
+		asserts.Equal(20, converted[1].Amount)
+		asserts.Equal(100, expenses[0].Amount)
+		asserts.Equal(models.Rates{rates[1]}, used)
+	})
+
+	t.Run("✅ should convert an updated expense at the rate of the day it was spent", func(t *testing.T) {
+		// Given
+		expenses := models.Expenses{{Id: 1, Amount: 100, Currency: "EUR", CreatedAt: august, UpdatedAt: &september}}
+
+		// When
+		converted, used, err := rates.Convert(expenses, "USD", "USD")
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(109, converted[0].Amount)
+		asserts.Equal(models.Rates{rates[0]}, used)
+	})
+
+	t.Run("✅ should convert the split shares keeping them equal to the amount", func(t *testing.T) {
+		// Given
+		expenses := models.Expenses{{Id: 1, Amount: 100, Currency: "EUR", CreatedAt: september, PaidBy: "alice",
+			Split: models.Shares{{Person: "alice", Amount: 33}, {Person: "bob", Amount: 33}, {Person: "carol", Amount: 34}}}}
+
+		// When
+		converted, _, err := rates.Convert(expenses, "USD", "USD")
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(models.Shares{{Person: "alice", Amount: 37}, {Person: "bob", Amount: 37}, {Person: "carol", Amount: 37}}, converted[0].Split)
+		asserts.Equal(converted[0].Amount, converted[0].Split.Total())
+		asserts.Equal(33, expenses[0].Split[0].Amount)
+	})
+
+	t.Run("❌ should not accept an invalid currency code", func(t *testing.T) {
+		// When
+		_, err := models.ParseCurrency("EURO")
+
+		// Then
+		asserts.Error(err)
+	})
+}
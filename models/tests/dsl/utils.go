@@ -42,11 +42,11 @@ func OutputToString(callback func()) string {
 func JoinMessage(expenses models.Expenses) string {
 	message := []string{}
 	for _, expense := range expenses {
-		if expense.UpdatedAt == nil {
-			message = append(message, fmt.Sprintf(models.ExpensesStringFormat, expense.Id, expense.Description, expense.Amount, expense.CreatedAt.Format(models.DateFormat), ""))
-			continue
+		updatedAt := ""
+		if expense.UpdatedAt != nil {
+			updatedAt = expense.UpdatedAt.Format(models.DateFormat)
 		}
-		message = append(message, fmt.Sprintf(models.ExpensesStringFormat, expense.Id, expense.Description, expense.Amount, expense.CreatedAt.Format(models.DateFormat), expense.UpdatedAt.Format(models.DateFormat)))
+		message = append(message, fmt.Sprintf(models.ExpensesStringFormat, expense.Id, expense.Description, models.FormatAmount(expense.Amount), expense.CreatedAt.Format(models.DateFormat), updatedAt, expense.Category, expense.Currency, expense.Kind, models.FormatAmount(expense.Net(nil))))
 	}
 	return strings.Join(message, "\n")
 }
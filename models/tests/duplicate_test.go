@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicate(t *testing.T) {
+	asserts := assert.New(t)
+
+	monday := time.Date(2024, time.August, 5, 12, 0, 0, 0, time.UTC)
+	tuesday := time.Date(2024, time.August, 6, 9, 0, 0, 0, time.UTC)
+	friday := time.Date(2024, time.August, 9, 9, 0, 0, 0, time.UTC)
+
+	t.Run("✅ should detect the same amount with a similar description on a near date", func(t *testing.T) {
+		// Given
+		expense := &models.Expense{Id: 1, Amount: 20, Description: "Lunch at Joe's", CreatedAt: monday}
+
+		// Then
+		asserts.True(expense.IsLikelyDuplicate(&models.Expense{Amount: 20, Description: "lunch at  joes", CreatedAt: tuesday}, 1))
+		asserts.False(expense.IsLikelyDuplicate(&models.Expense{Amount: 20, Description: "Lunch at Joe's", CreatedAt: friday}, 1))
+		asserts.False(expense.IsLikelyDuplicate(&models.Expense{Amount: 21, Description: "Lunch at Joe's", CreatedAt: monday}, 1))
+		asserts.False(expense.IsLikelyDuplicate(&models.Expense{Amount: 20, Currency: "JPY", Description: "Lunch at Joe's", CreatedAt: monday}, 1))
+		asserts.False(expense.IsLikelyDuplicate(&models.Expense{Amount: 20, Description: "Taxi", CreatedAt: monday}, 1))
+	})
+
+	t.Run("✅ should group the suspected duplicates of the ledger", func(t *testing.T) {
+		// Given
+		expenses := models.Expenses{
+			{Id: 1, Amount: 20, Description: "Lunch", CreatedAt: monday},
+			{Id: 2, Amount: 5, Description: "Bus", CreatedAt: monday},
+			{Id: 3, Amount: 20, Description: "Lunhc", CreatedAt: tuesday},
+			{Id: 4, Amount: 5, Description: "Bus", CreatedAt: friday},
+			{Id: 5, Amount: 20, Description: "Lunch", CreatedAt: monday},
+		}
+
+		// When
+		groups := expenses.DuplicateGroups(1)
+
+		// Then
+		asserts.Equal(1, len(groups))
+		asserts.Equal(models.Expenses{expenses[0], expenses[2], expenses[4]}, groups[0])
+	})
+
+	t.Run("✅ should fill in what the kept expense is missing when merging", func(t *testing.T) {
+		// Given
+		kept := &models.Expense{Id: 1, Amount: 20, Description: "Lunch", Account: "visa"}
+
+		// When
+		kept.Merge(models.Expenses{{Id: 3, Amount: 20, Description: "Lunch", Category: "food", Account: "cash"}})
+
+		// Then
+		asserts.Equal("food", kept.Category)
+		asserts.Equal("visa", kept.Account)
+	})
+	t.Run("❌ should refuse to merge an expense into itself or twice", func(t *testing.T) {
+		// When
+		itself := models.ValidateMerge([]int{1, 1})
+		twice := models.ValidateMerge([]int{1, 3, 3})
+		alone := models.ValidateMerge([]int{1})
+		valid := models.ValidateMerge([]int{1, 3, 7})
+
+		// Then
+		asserts.ErrorContains(itself, "cannot be merged into itself")
+		asserts.ErrorContains(twice, "more than once")
+		asserts.ErrorContains(alone, "at least two IDs")
+		asserts.Nil(valid)
+	})
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForecast(t *testing.T) {
+	asserts := assert.New(t)
+
+	now := time.Date(2024, time.August, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("✅ should project the pace so far plus the recurring expenses still to come", func(t *testing.T) {
+		// Given
+		expenses := models.Expenses{
+			{Id: 1, Amount: 500, Description: "Rent", Recurring: true, CreatedAt: time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)},
+			{Id: 2, Amount: 30, Description: "Gym", Recurring: true, CreatedAt: time.Date(2024, time.July, 20, 0, 0, 0, 0, time.UTC)},
+			{Id: 3, Amount: 500, Description: "Rent", Recurring: true, CreatedAt: time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)},
+			{Id: 4, Amount: 100, Description: "Groceries", CreatedAt: time.Date(2024, time.August, 5, 0, 0, 0, 0, time.UTC)},
+		}
+
+		// When
+		forecast := expenses.Forecast(now, nil)
+
+		// Then
+		asserts.Equal(600, forecast.Spent)
+		asserts.Equal(10.0, forecast.DailyPace)
+		asserts.Equal(30, forecast.PendingRecurring)
+		asserts.Equal(840, forecast.Projected)
+		asserts.Equal(22, forecast.DaysLeft)
+		asserts.False(forecast.HasBudget)
+	})
+
+	t.Run("✅ should project only the budgeted categories against their budgets", func(t *testing.T) {
+		// Given
+		expenses := models.Expenses{
+			{Id: 1, Amount: 500, Description: "Rent", Category: "housing", Recurring: true, CreatedAt: time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)},
+			{Id: 2, Amount: 30, Description: "Gym", Category: "sport", Recurring: true, CreatedAt: time.Date(2024, time.July, 20, 0, 0, 0, 0, time.UTC)},
+			{Id: 3, Amount: 500, Description: "Rent", Category: "housing", Recurring: true, CreatedAt: time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)},
+			{Id: 4, Amount: 100, Description: "Groceries", Category: "Food", CreatedAt: time.Date(2024, time.August, 5, 0, 0, 0, 0, time.UTC)},
+		}
+		budgets := []models.BudgetStatus{{Category: "food", Budget: 300, Rollover: 50}, {Category: "sport", Budget: 40}}
+
+		// When
+		forecast := expenses.Forecast(now, budgets)
+
+		// Then
+		asserts.Equal(100, forecast.Spent)
+		asserts.Equal(30, forecast.PendingRecurring)
+		asserts.Equal(340, forecast.Projected)
+		asserts.Equal(390, forecast.Budget)
+		asserts.Equal([]string{"food", "sport"}, forecast.Categories)
+		asserts.InDelta(11.82, forecast.DailyAllowance, 0.01)
+	})
+
+	t.Run("✅ should not compute an allowance without a budget", func(t *testing.T) {
+		// When
+		forecast := models.Expenses{}.Forecast(now, nil)
+
+		// Then
+		asserts.Equal(0, forecast.Projected)
+		asserts.Equal(0.0, forecast.DailyAllowance)
+	})
+}
@@ -0,0 +1,31 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery(t *testing.T) {
+	asserts := assert.New(t)
+
+	day := func(d int) time.Time { return time.Date(2024, time.August, d, 0, 0, 0, 0, time.UTC) }
+	expenses := models.Expenses{
+		{Id: 1, Amount: 90, Description: "Rent", CreatedAt: day(1)},
+		{Id: 2, Amount: 10, Description: "Lunch", CreatedAt: day(5)},
+		{Id: 3, Amount: 30, Description: "Taxi", CreatedAt: day(3)},
+		{Id: 4, Amount: 20, Description: "Dinner", CreatedAt: day(5)},
+	}
+
+	t.Run("✅ should keep the most recent expenses with tail whatever the sort", func(t *testing.T) {
+		// When
+		byAmount := expenses.Find(models.Query{SortBy: models.SortByAmount, Tail: 2})
+		descending := expenses.Find(models.Query{Desc: true, Tail: 2})
+
+		// Then
+		asserts.Equal(models.Expenses{expenses[1], expenses[3]}, byAmount)
+		asserts.Equal(models.Expenses{expenses[3], expenses[1]}, descending)
+	})
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("✅ should split equally giving the remainder to the first people", func(t *testing.T) {
+		// When
+		shares, err := models.ParseSplit("alice,bob,me", 100)
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(models.Shares{{Person: "alice", Amount: 34}, {Person: "bob", Amount: 33}, {Person: "me", Amount: 33}}, shares)
+	})
+
+	t.Run("✅ should split by percentages", func(t *testing.T) {
+		// When
+		shares, err := models.ParseSplit("alice:60%,bob:40%", 50)
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(models.Shares{{Person: "alice", Amount: 30}, {Person: "bob", Amount: 20}}, shares)
+	})
+
+	t.Run("❌ should not split exact shares that do not add up to the amount", func(t *testing.T) {
+		// When
+		_, err := models.ParseSplit("alice:12,bob:8", 3000)
+
+		// Then
+		asserts.Equal("split shares add up to 20 instead of 30", err.Error())
+	})
+
+	t.Run("❌ should not mix split styles", func(t *testing.T) {
+		// When
+		_, err := models.ParseSplit("alice:50%,bob:10", 20)
+
+		// Then
+		asserts.Error(err)
+	})
+
+	t.Run("✅ should settle the balances with the fewest transfers", func(t *testing.T) {
+		// Given
+		expenses := models.Expenses{
+			{Id: 1, Amount: 90, PaidBy: "me", Split: models.Shares{{Person: "alice", Amount: 30}, {Person: "bob", Amount: 30}, {Person: "me", Amount: 30}}},
+			{Id: 2, Amount: 60, PaidBy: "alice", Split: models.Shares{{Person: "alice", Amount: 30}, {Person: "bob", Amount: 30}}},
+			{Id: 3, Amount: 15},
+		}
+
+		// When
+		balances := expenses.PersonBalances()
+		settlements := models.Settle(balances)
+
+		// Then
+		asserts.Equal([]models.PersonBalance{{Person: "alice", Balance: 0}, {Person: "bob", Balance: -60}, {Person: "me", Balance: 60}}, balances)
+		asserts.Equal([]models.Settlement{{From: "bob", To: "me", Amount: 60}}, settlements)
+	})
+	t.Run("✅ should settle groups of balances adding up to zero on their own", func(t *testing.T) {
+		// Given
+		balances := []models.PersonBalance{
+			{Person: "alice", Balance: 400},
+			{Person: "bob", Balance: 300},
+			{Person: "carol", Balance: 200},
+			{Person: "dave", Balance: -500},
+			{Person: "erin", Balance: -400},
+		}
+
+		// When
+		settlements := models.Settle(balances)
+
+		// Then
+		asserts.Equal([]models.Settlement{
+			{From: "erin", To: "alice", Amount: 400},
+			{From: "dave", To: "bob", Amount: 300},
+			{From: "dave", To: "carol", Amount: 200},
+		}, settlements)
+	})
+}
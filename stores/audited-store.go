@@ -0,0 +1,106 @@
+package stores
+
+import (
+	"expense-tracker/models"
+	"time"
+)
+
+// AuditedStore records a revision of every expense changed through the store
+// it wraps.
+type AuditedStore struct {
+	models.Store
+	audit  models.AuditStore
+	author string
+}
+
+func NewAuditedStore(store models.Store, audit models.AuditStore, author string) models.Store {
+	return &AuditedStore{
+		Store:  store,
+		audit:  audit,
+		author: author,
+	}
+}
+
+func (s *AuditedStore) Unwrap() models.Store {
+	return s.Store
+}
+
+func (s *AuditedStore) Add(expense models.Expense) error {
+	if err := s.Store.Add(expense); err != nil {
+		return err
+	}
+
+	added, err := s.Store.Get(s.Store.LastId())
+	if err != nil {
+		return err
+	}
+	return s.record(models.ActionAdd, added)
+}
+
+func (s *AuditedStore) Update(expense models.Expense) error {
+	if err := s.Store.Update(expense); err != nil {
+		return err
+	}
+
+	updated, err := s.Store.Get(expense.Id)
+	if err != nil {
+		return err
+	}
+	return s.record(models.ActionUpdate, updated)
+}
+
+func (s *AuditedStore) Delete(id int) error {
+	if err := s.Store.Delete(id); err != nil {
+		return err
+	}
+
+	for _, expense := range s.Store.Trashed() {
+		if expense.Id == id {
+			return s.record(models.ActionDelete, expense)
+		}
+	}
+	return nil
+}
+
+func (s *AuditedStore) Restore(id int) error {
+	if err := s.Store.Restore(id); err != nil {
+		return err
+	}
+
+	restored, err := s.Store.Get(id)
+	if err != nil {
+		return err
+	}
+	return s.record(models.ActionRestore, restored)
+}
+
+func (s *AuditedStore) EmptyTrash(deletedBefore time.Time) (int, error) {
+	purged := models.Expenses{}
+	for _, expense := range s.Store.Trashed() {
+		if expense.DeletedAt.Before(deletedBefore) {
+			purged = append(purged, expense)
+		}
+	}
+
+	emptied, err := s.Store.EmptyTrash(deletedBefore)
+	if err != nil {
+		return emptied, err
+	}
+
+	for _, expense := range purged {
+		if err := s.record(models.ActionPurge, expense); err != nil {
+			return emptied, err
+		}
+	}
+	return emptied, nil
+}
+
+func (s *AuditedStore) record(action models.Action, expense *models.Expense) error {
+	return s.audit.Record(models.Revision{
+		ExpenseId: expense.Id,
+		Action:    action,
+		Author:    s.author,
+		At:        time.Now(),
+		Expense:   *expense,
+	})
+}
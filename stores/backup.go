@@ -0,0 +1,256 @@
+package stores
+
+import (
+	"expense-tracker/models"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BackupPolicy is how many backups of a file are kept and for how long, zero
+// meaning no limit.
+type BackupPolicy struct {
+	Count  int
+	MaxAge time.Duration
+}
+
+const (
+	DefaultBackupCount = 10
+
+	backupIdFormat = "20060102-150405.000"
+)
+
+// WithBackups keeps a backup of the file as it was before every change,
+// rotated by the policy.
+func WithBackups(policy BackupPolicy) FileOption {
+	return func(o *fileOptions) {
+		o.backups = &policy
+	}
+}
+
+// CreateBackup copies the file as it is, encrypted or not, to the backups
+// directory next to it.
+func (s *CsvStore) CreateBackup() (models.Backup, error) {
+	content, err := os.ReadFile(s.filename)
+	if err != nil {
+		return models.Backup{}, err
+	}
+	if err := os.MkdirAll(s.backupDirectory(), 0755); err != nil {
+		return models.Backup{}, err
+	}
+
+	// changes made within the same millisecond as the latest backup come
+	// after it, rather than in an ID freed by the rotation
+	createdAt := time.Now()
+	backups, err := s.Backups()
+	if err != nil {
+		return models.Backup{}, err
+	}
+	if len(backups) > 0 && !createdAt.Truncate(time.Millisecond).After(backups[0].CreatedAt) {
+		createdAt = backups[0].CreatedAt.Add(time.Millisecond)
+	}
+
+	backup := models.Backup{Id: createdAt.Format(backupIdFormat), CreatedAt: createdAt, Size: int64(len(content))}
+	return backup, os.WriteFile(s.backupFilename(backup.Id), content, 0644)
+}
+
+// Backups lists the backups of the file, latest first.
+func (s *CsvStore) Backups() ([]models.Backup, error) {
+	entries, err := os.ReadDir(s.backupDirectory())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := filepath.Base(s.filename) + "."
+	backups := []models.Backup{}
+	for _, entry := range entries {
+		id, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok {
+			continue
+		}
+		createdAt, err := time.ParseInLocation(backupIdFormat, id, time.Local)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, models.Backup{Id: id, CreatedAt: createdAt, Size: info.Size()})
+	}
+
+	slices.SortFunc(backups, func(a models.Backup, b models.Backup) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return backups, nil
+}
+
+// BackupExpenses reads the expenses of a backup, trashed ones included.
+func (s *CsvStore) BackupExpenses(id string) (models.Expenses, error) {
+	if err := s.requireBackup(id); err != nil {
+		return nil, err
+	}
+
+	records, err := readCsv(s.backupFilename(id), s.codec)
+	if err != nil {
+		return nil, err
+	}
+	expenses, trash, err := fromRecords(records[min(1, len(records)):])
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(expenses, trash), nil
+}
+
+// RestoreBackup replaces the file with a backup, backing up the file first so
+// the restore can be reverted.
+func (s *CsvStore) RestoreBackup(id string) error {
+	if err := s.requireBackup(id); err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(s.backupFilename(id))
+	if err != nil {
+		return err
+	}
+	head, err := s.backupHead(id)
+	if err != nil {
+		return err
+	}
+	if err := s.backup(); err != nil {
+		return err
+	}
+	if err := replaceFile(s.filename, content); err != nil {
+		return err
+	}
+	if err := s.restoreHead(head); err != nil {
+		return err
+	}
+
+	s.altered = nil
+	return s.load()
+}
+
+// backupHead verifies the chain of a backup and returns its head, nil for
+// backups of files never sealed.
+func (s *CsvStore) backupHead(id string) (*chainHead, error) {
+	records, err := readCsv(s.backupFilename(id), s.codec)
+	if err != nil {
+		return nil, err
+	}
+	records = records[min(1, len(records)):]
+	if !sealed(records) {
+		return nil, nil
+	}
+	if _, err := verifyChain(records, nil); err != nil {
+		return nil, fmt.Errorf("backup %s was altered, refusing to restore it: %w", id, err)
+	}
+	head := headOf(records)
+	return &head, nil
+}
+
+// restoreHead keeps the head of the restored file next to it.
+func (s *CsvStore) restoreHead(head *chainHead) error {
+	if head != nil {
+		return writeHead(s.filename, *head)
+	}
+	if err := os.Remove(headFilename(s.filename)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// backup keeps the file as it is before it changes, when backups are enabled.
+func (s *CsvStore) backup() error {
+	if s.backups == nil {
+		return nil
+	}
+	if _, err := os.Stat(s.filename); os.IsNotExist(err) {
+		return nil
+	}
+
+	if _, err := s.CreateBackup(); err != nil {
+		return err
+	}
+	return s.rotateBackups()
+}
+
+// recodeBackups writes the backups again with another codec, or in plain
+// text without one.
+func (s *CsvStore) recodeBackups(from models.Codec, to models.Codec) error {
+	backups, err := s.Backups()
+	if err != nil {
+		return err
+	}
+
+	for _, backup := range backups {
+		filename := s.backupFilename(backup.Id)
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		switch {
+		case from != nil && from.Encoded(content):
+			content, err = from.Decode(content)
+			if err != nil {
+				return fmt.Errorf("%s cannot be decoded: %w", filename, err)
+			}
+		case encrypted(content):
+			return fmt.Errorf("%s is encrypted, a passphrase or key file is required", filename)
+		}
+		if to != nil {
+			content, err = to.Encode(content)
+			if err != nil {
+				return err
+			}
+		}
+		if err := replaceFile(filename, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rotateBackups removes the backups beyond the count or older than the age of
+// the policy.
+func (s *CsvStore) rotateBackups() error {
+	backups, err := s.Backups()
+	if err != nil {
+		return err
+	}
+
+	for i, backup := range backups {
+		tooMany := s.backups.Count > 0 && i >= s.backups.Count
+		tooOld := s.backups.MaxAge > 0 && time.Since(backup.CreatedAt) > s.backups.MaxAge
+		if tooMany || tooOld {
+			if err := os.Remove(s.backupFilename(backup.Id)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *CsvStore) requireBackup(id string) error {
+	if _, err := time.Parse(backupIdFormat, id); err != nil {
+		return fmt.Errorf("invalid backup ID %q", id)
+	}
+	if _, err := os.Stat(s.backupFilename(id)); err != nil {
+		return fmt.Errorf("backup %s not found", id)
+	}
+	return nil
+}
+
+func (s *CsvStore) backupDirectory() string {
+	return filepath.Join(filepath.Dir(s.filename), "backups")
+}
+
+func (s *CsvStore) backupFilename(id string) string {
+	return filepath.Join(s.backupDirectory(), filepath.Base(s.filename)+"."+id)
+}
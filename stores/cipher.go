@@ -0,0 +1,151 @@
+package stores
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"expense-tracker/models"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// the Argon2id parameters deriving a key from a passphrase, the second
+	// recommended option of RFC 9106
+	argonTime    = 3
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+
+	keySize  = 32
+	saltSize = 16
+)
+
+// cipherMagic starts every encrypted file, followed by the salt of the key,
+// the nonce and the sealed content.
+var cipherMagic = []byte("EXPENSE-TRACKER-AES-GCM-1\n")
+
+var (
+	// ErrKeyRequired is returned reading an encrypted file without a key.
+	ErrKeyRequired = errors.New("a passphrase or key file is required")
+	// ErrWrongKey is returned reading a file encrypted with another key.
+	ErrWrongKey = errors.New("wrong passphrase or key, or altered content")
+)
+
+// Cipher encrypts files with AES-256-GCM, with either a key derived from a
+// passphrase, salted per file, or the key of a key file.
+type Cipher struct {
+	passphrase string
+	key        []byte
+	salt       []byte
+}
+
+func NewPassphraseCipher(passphrase string) (models.Codec, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase cannot be empty")
+	}
+	return &Cipher{passphrase: passphrase}, nil
+}
+
+func NewKeyCipher(key []byte) (models.Codec, error) {
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key must be %d bytes", keySize)
+	}
+	return &Cipher{key: key, salt: make([]byte, saltSize)}, nil
+}
+
+// ReadKeyFile reads a key file holding the key either as is or hex encoded.
+func ReadKeyFile(filename string) ([]byte, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) == keySize {
+		return content, nil
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil || len(key) != keySize {
+		return nil, fmt.Errorf("%s must hold a %d bytes key, raw or hex encoded", filename, keySize)
+	}
+	return key, nil
+}
+
+func (c *Cipher) Encode(plain []byte) ([]byte, error) {
+	if c.salt == nil {
+		salt := make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		c.derive(salt)
+	}
+
+	aead, err := c.aead()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	header := append(bytes.Clone(cipherMagic), c.salt...)
+	sealed := append(header, nonce...)
+	return aead.Seal(sealed, nonce, plain, header), nil
+}
+
+func (c *Cipher) Decode(encoded []byte) ([]byte, error) {
+	headerSize := len(cipherMagic) + saltSize
+	if !c.Encoded(encoded) || len(encoded) < headerSize {
+		return nil, fmt.Errorf("content is not encrypted")
+	}
+
+	header := encoded[:headerSize]
+	c.derive(header[len(cipherMagic):])
+
+	aead, err := c.aead()
+	if err != nil {
+		return nil, err
+	}
+	rest := encoded[headerSize:]
+	if len(rest) < aead.NonceSize() {
+		return nil, fmt.Errorf("content is truncated")
+	}
+
+	plain, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], header)
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return plain, nil
+}
+
+func (c *Cipher) Encoded(content []byte) bool {
+	return encrypted(content)
+}
+
+// derive sets the key for the salt of a file, keeping the salt so the file is
+// written back without deriving the key again. Keys from key files are used
+// as they are.
+func (c *Cipher) derive(salt []byte) {
+	if c.passphrase == "" || bytes.Equal(c.salt, salt) {
+		return
+	}
+	c.key = argon2.IDKey([]byte(c.passphrase), salt, argonTime, argonMemory, argonThreads, keySize)
+	c.salt = bytes.Clone(salt)
+}
+
+func (c *Cipher) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(c.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encrypted(content []byte) bool {
+	return bytes.HasPrefix(content, cipherMagic)
+}
@@ -0,0 +1,73 @@
+package stores
+
+import (
+	"expense-tracker/models"
+	"fmt"
+)
+
+type CsvAccountStore struct {
+	accounts models.Accounts
+	codedFile
+}
+
+var accountHeaders = []string{"Name", "Opening Balance"}
+
+func NewCsvAccountStore(filename string, options ...FileOption) models.AccountStore {
+	store := &CsvAccountStore{
+		accounts:  models.Accounts{},
+		codedFile: newCodedFile(filename, options),
+	}
+
+	err := store.load()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return store
+}
+
+// Save defines an account, replacing the definition with the same name.
+func (s *CsvAccountStore) Save(account models.Account) error {
+	if account.Name == "" {
+		return fmt.Errorf("account name is required")
+	}
+
+	for i, item := range s.accounts {
+		if item.Name == account.Name {
+			s.accounts[i] = account
+			return s.save()
+		}
+	}
+
+	s.accounts = append(s.accounts, account)
+	return s.save()
+}
+
+func (s *CsvAccountStore) Accounts() models.Accounts {
+	return s.accounts
+}
+
+func (s *CsvAccountStore) load() error {
+	records, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		openingBalance, err := models.ParseAmount(record[1])
+		if err != nil {
+			return err
+		}
+		s.accounts = append(s.accounts, models.Account{Name: record[0], OpeningBalance: openingBalance})
+	}
+	return nil
+}
+
+func (s *CsvAccountStore) save() error {
+	records := [][]string{accountHeaders}
+	for _, account := range s.accounts {
+		records = append(records, []string{account.Name, models.FormatAmount(account.OpeningBalance)})
+	}
+	return s.write(records)
+}
@@ -0,0 +1,130 @@
+package stores
+
+import (
+	"expense-tracker/models"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// CsvAuditStore keeps the revisions in a file only ever appended to, each
+// row holding the revision followed by the columns of the expense.
+type CsvAuditStore struct {
+	revisions models.Revisions
+	filename  string
+	fileOptions
+}
+
+var auditHeaders = slices.Concat([]string{"Expense ID", "Version", "Action", "Author", "At"}, csvHeaders)
+
+func NewCsvAuditStore(filename string, options ...FileOption) models.AuditStore {
+	store := &CsvAuditStore{
+		revisions:   models.Revisions{},
+		filename:    filename,
+		fileOptions: newFileOptions(options),
+	}
+
+	err := store.load()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return store
+}
+
+// Record appends a revision, numbering it after the previous revisions of
+// the same expense.
+func (s *CsvAuditStore) Record(revision models.Revision) error {
+	revision.Version = len(s.Revisions(revision.ExpenseId)) + 1
+
+	record := slices.Concat([]string{
+		strconv.Itoa(revision.ExpenseId),
+		strconv.Itoa(revision.Version),
+		string(revision.Action),
+		revision.Author,
+		revision.At.Format(time.RFC3339),
+	}, toRecord(&revision.Expense))
+	if err := appendCsv(s.filename, s.codec, auditHeaders, [][]string{record}); err != nil {
+		return err
+	}
+
+	s.revisions = append(s.revisions, revision)
+	return nil
+}
+
+func (s *CsvAuditStore) Revisions(expenseId int) models.Revisions {
+	revisions := models.Revisions{}
+	for _, revision := range s.revisions {
+		if revision.ExpenseId == expenseId {
+			revisions = append(revisions, revision)
+		}
+	}
+	return revisions
+}
+
+// Encrypt writes the revisions with the codec of the store.
+func (s *CsvAuditStore) Encrypt() error {
+	if s.codec == nil {
+		return fmt.Errorf("no passphrase or key file to encrypt %s with", s.filename)
+	}
+	return s.rewrite(s.codec)
+}
+
+// Decrypt writes the revisions back in plain text.
+func (s *CsvAuditStore) Decrypt() error {
+	if err := s.rewrite(nil); err != nil {
+		return err
+	}
+	s.codec = nil
+	return nil
+}
+
+func (s *CsvAuditStore) rewrite(codec models.Codec) error {
+	content, err := readFile(s.filename, s.codec)
+	if err != nil || content == nil {
+		return err
+	}
+	return writeFile(s.filename, codec, content)
+}
+
+func (s *CsvAuditStore) load() error {
+	records, err := readCsv(s.filename, s.codec)
+	if err != nil {
+		return err
+	}
+
+	// remove headers
+	if len(records) > 0 {
+		records = records[1:]
+	}
+
+	for _, record := range records {
+		expenseId, err := strconv.Atoi(record[0])
+		if err != nil {
+			return err
+		}
+		version, err := strconv.Atoi(record[1])
+		if err != nil {
+			return err
+		}
+		at, err := time.Parse(time.RFC3339, record[4])
+		if err != nil {
+			return err
+		}
+		expense, err := fromRecord(record[5:])
+		if err != nil {
+			return err
+		}
+		s.revisions = append(s.revisions, models.Revision{
+			ExpenseId: expenseId,
+			Version:   version,
+			Action:    models.Action(record[2]),
+			Author:    record[3],
+			At:        at,
+			Expense:   *expense,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package stores
+
+import (
+	"expense-tracker/models"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CsvBudgetStore struct {
+	budgets models.Budgets
+	codedFile
+}
+
+var budgetHeaders = []string{"Category", "Amount", "Rollover", "From"}
+
+const budgetMonthFormat = "2006-01"
+
+func NewCsvBudgetStore(filename string, options ...FileOption) models.BudgetStore {
+	store := &CsvBudgetStore{
+		budgets:   models.Budgets{},
+		codedFile: newCodedFile(filename, options),
+	}
+
+	err := store.load()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return store
+}
+
+// Set records a budget, replacing the one of the same category and month.
+func (s *CsvBudgetStore) Set(budget models.Budget) error {
+	if budget.Category == "" {
+		return fmt.Errorf("budget category is required")
+	}
+	if budget.Amount < 0 {
+		return fmt.Errorf("budget amount cannot be negative")
+	}
+
+	for i, item := range s.budgets {
+		if strings.EqualFold(item.Category, budget.Category) && item.From.Equal(budget.From) {
+			s.budgets[i] = budget
+			return s.save()
+		}
+	}
+
+	s.budgets = append(s.budgets, budget)
+	return s.save()
+}
+
+func (s *CsvBudgetStore) Budgets() models.Budgets {
+	return s.budgets
+}
+
+func (s *CsvBudgetStore) load() error {
+	records, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		amount, err := models.ParseAmount(record[1])
+		if err != nil {
+			return err
+		}
+		rollover, err := strconv.ParseBool(record[2])
+		if err != nil {
+			return err
+		}
+		from, err := time.Parse(budgetMonthFormat, record[3])
+		if err != nil {
+			return err
+		}
+		s.budgets = append(s.budgets, models.Budget{Category: record[0], Amount: amount, Rollover: rollover, From: from})
+	}
+	return nil
+}
+
+func (s *CsvBudgetStore) save() error {
+	records := [][]string{budgetHeaders}
+	for _, budget := range s.budgets {
+		records = append(records, []string{
+			budget.Category,
+			models.FormatAmount(budget.Amount),
+			strconv.FormatBool(budget.Rollover),
+			budget.From.Format(budgetMonthFormat),
+		})
+	}
+	return s.write(records)
+}
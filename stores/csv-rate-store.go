@@ -0,0 +1,87 @@
+package stores
+
+import (
+	"expense-tracker/models"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type CsvRateStore struct {
+	rates models.Rates
+	codedFile
+}
+
+var rateHeaders = []string{"From", "To", "Rate", "Date"}
+
+func NewCsvRateStore(filename string, options ...FileOption) models.RateStore {
+	store := &CsvRateStore{
+		rates:     models.Rates{},
+		codedFile: newCodedFile(filename, options),
+	}
+
+	err := store.load()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return store
+}
+
+// Set records a rate, replacing the one for the same pair and date.
+func (s *CsvRateStore) Set(rate models.Rate) error {
+	if rate.Rate <= 0 {
+		return fmt.Errorf("rate must be positive")
+	}
+	if rate.From == rate.To {
+		return fmt.Errorf("rate must be between two different currencies")
+	}
+
+	for i, item := range s.rates {
+		if item.From == rate.From && item.To == rate.To && item.Date.Equal(rate.Date) {
+			s.rates[i] = rate
+			return s.save()
+		}
+	}
+
+	s.rates = append(s.rates, rate)
+	return s.save()
+}
+
+func (s *CsvRateStore) Rates() models.Rates {
+	return s.rates
+}
+
+func (s *CsvRateStore) load() error {
+	records, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		value, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			return err
+		}
+		date, err := time.Parse(models.DateFormat, record[3])
+		if err != nil {
+			return err
+		}
+		s.rates = append(s.rates, models.Rate{From: record[0], To: record[1], Rate: value, Date: date})
+	}
+	return nil
+}
+
+func (s *CsvRateStore) save() error {
+	records := [][]string{rateHeaders}
+	for _, rate := range s.rates {
+		records = append(records, []string{
+			rate.From,
+			rate.To,
+			strconv.FormatFloat(rate.Rate, 'f', -1, 64),
+			rate.Date.Format(models.DateFormat),
+		})
+	}
+	return s.write(records)
+}
@@ -1,26 +1,41 @@
 package stores
 
 import (
-	"encoding/csv"
 	"expense-tracker/models"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
-	"strings"
 	"time"
 )
 
-type csvStore struct {
+type CsvStore struct {
 	Expenses *models.Expenses
+	Trash    *models.Expenses
 	filename string
+	index    *index
+	// sequence is the last ID kept next to the file
+	sequence int
+	// head is the end of the chain of hashes kept next to the file, nil
+	// until the file is sealed
+	head *chainHead
+	fileOptions
+	// altered is why the file cannot be saved over, its chain of hashes
+	// being broken when loaded
+	altered error
 }
 
-func NewCsvStore(filename string) models.Store {
-	fileExistAndCreate(filename)
+var csvHeaders = []string{"ID", "Description", "Amount", "Created At", "Updated At", "Category", "Currency", "Kind", "Refund Of", "Account", "To Account", "Paid By", "Split", "Recurring", "Deleted At"}
 
-	store := &csvStore{
-		Expenses: &models.Expenses{},
-		filename: filename,
+// ledgerHeaders are the columns of the CSV store, sealed by a hash column.
+var ledgerHeaders = slices.Concat(csvHeaders, []string{"Hash"})
+
+func NewCsvStore(filename string, options ...FileOption) models.Store {
+	store := &CsvStore{
+		Expenses:    &models.Expenses{},
+		Trash:       &models.Expenses{},
+		filename:    filename,
+		fileOptions: newFileOptions(options),
 	}
 
 	err := store.load()
@@ -32,15 +47,21 @@ func NewCsvStore(filename string) models.Store {
 	return store
 }
 
-func (s *csvStore) Add(expense models.Expense) error {
-	expense.Id = s.assignId()
+func (s *CsvStore) Add(expense models.Expense) error {
+	expense.Id = s.index.nextId()
 	expense.CreatedAt = time.Now()
+	expense.Kind = expense.TransactionKind()
 
-	if expense.Amount < 0 {
-		return fmt.Errorf("amount cannot be negative")
+	if err := expense.Validate(); err != nil {
+		return err
+	}
+	if expense.Kind == models.KindRefund {
+		if err := s.Expenses.ValidateRefund(&expense); err != nil {
+			return err
+		}
 	}
 
-	*s.Expenses = append(*s.Expenses, &expense)
+	s.insert(&expense)
 	err := s.save()
 
 	if err != nil {
@@ -50,118 +71,126 @@ func (s *csvStore) Add(expense models.Expense) error {
 	return nil
 }
 
-func (s *csvStore) assignId() int {
-	currentMaxId := 0
-	for _, expense := range *s.Expenses {
-		if expense.Id > currentMaxId {
-			currentMaxId = expense.Id
-		}
-	}
-	return (currentMaxId + 1)
+func (s *CsvStore) insert(expense *models.Expense) {
+	s.index.insert(s.Expenses, expense)
 }
 
-func (s *csvStore) Update(expense models.Expense) error {
-	if expense.Amount < 0 {
-		return fmt.Errorf("amount cannot be negative")
-	}
+// Reindex rebuilds the index after the expenses were changed directly rather
+// than through the store.
+func (s *CsvStore) Reindex() {
+	s.index = s.index.rebuild(*s.Expenses, *s.Trash)
+}
 
-	for _, item := range *s.Expenses {
-		if item.Id == expense.Id {
-			item.Amount = expense.Amount
-			item.Description = expense.Description
-			updatedAt := time.Now()
-			item.UpdatedAt = &updatedAt
-			err := s.save()
-			if err != nil {
-				return err
-			}
-			return nil
-		}
+// apply copies an update over the expense with its ID, telling whether there
+// is one.
+func (s *CsvStore) apply(update models.Expense) bool {
+	item, found := s.index.get(update.Id)
+	if !found {
+		return false
 	}
 
-	return fmt.Errorf("expense with ID %d not found", expense.Id)
+	s.index.remove(item)
+	item.Apply(update)
+	s.index.add(item)
+	return true
 }
 
-func (s *csvStore) Delete(id int) error {
-	foundItem := false
-	for i, item := range *s.Expenses {
-		if item.Id == id {
-			foundItem = true
-			*s.Expenses = append((*s.Expenses)[:i], (*s.Expenses)[i+1:]...)
-
-			err := s.save()
-
-			if err != nil {
-				return err
-			}
-
-			return nil
+func (s *CsvStore) Update(expense models.Expense) error {
+	if err := expense.Validate(); err != nil {
+		return err
+	}
+	if expense.TransactionKind() == models.KindRefund {
+		if err := s.Expenses.ValidateRefund(&expense); err != nil {
+			return err
 		}
 	}
 
-	if !foundItem {
-		return fmt.Errorf("expense with ID %d not found", id)
+	if !s.apply(expense) {
+		return fmt.Errorf("expense with ID %d not found", expense.Id)
 	}
 
-	return nil
+	return s.save()
 }
 
-func (s *csvStore) List() {
-	fmt.Printf(models.HeaderFormat + "\n")
-	for _, expense := range *s.Expenses {
-		expense.Print()
+func (s *CsvStore) Delete(id int) error {
+	item, found := s.index.take(s.Expenses, id)
+	if !found {
+		return fmt.Errorf("expense with ID %d not found", id)
 	}
+
+	deletedAt := time.Now()
+	item.DeletedAt = &deletedAt
+	*s.Trash = append(*s.Trash, item)
+
+	return s.save()
+}
+
+func (s *CsvStore) Trashed() models.Expenses {
+	return s.Trash.Find(models.Query{})
 }
 
-func (s *csvStore) Summary() {
-	total := 0
-	for _, expense := range *s.Expenses {
-		total += expense.Amount
+func (s *CsvStore) Restore(id int) error {
+	item, found := s.Trash.Take(id)
+	if !found {
+		return fmt.Errorf("expense with ID %d not found in trash", id)
 	}
-	fmt.Printf("Total expenses: %d\n", total)
+
+	item.DeletedAt = nil
+	s.insert(item)
+
+	return s.save()
 }
 
-func (s *csvStore) SummaryForMonth(month time.Month) {
-	total := 0
-	for _, expense := range *s.Expenses {
-		if expense.UpdatedAt == nil {
-			if s.isValidSummaryInput(&expense.CreatedAt, month) {
-				total += expense.Amount
-			}
+func (s *CsvStore) EmptyTrash(deletedBefore time.Time) (int, error) {
+	kept := models.Expenses{}
+	for _, item := range *s.Trash {
+		if item.DeletedAt.Before(deletedBefore) {
 			continue
 		}
-
-		if s.isValidSummaryInput(expense.UpdatedAt, month) {
-			total += expense.Amount
-		}
+		kept = append(kept, item)
 	}
-	fmt.Printf("Total expenses: %d\n", total)
+
+	emptied := len(*s.Trash) - len(kept)
+	*s.Trash = kept
+
+	return emptied, s.save()
+}
+
+func (s *CsvStore) List() {
+	s.ListBy(models.Query{})
 }
 
-func (s *csvStore) isValidSummaryInput(date *time.Time, month time.Month) bool {
-	isCurrentYear := date.Year() == time.Now().Year()
-	isSameMonth := date.Month() == month
+func (s *CsvStore) ListBy(query models.Query) {
+	s.Find(query).PrintRefunded(s.Expenses.Refunded())
+}
 
-	if isSameMonth && isCurrentYear {
-		return true
+func (s *CsvStore) Get(id int) (*models.Expense, error) {
+	expense, found := s.index.get(id)
+	if !found {
+		return nil, fmt.Errorf("expense with ID %d not found", id)
 	}
-	return false
+	copied := *expense
+	return &copied, nil
 }
 
-func fileExistAndCreate(fileName string) {
-	if _, err := os.Stat(fileName); os.IsNotExist(err) {
-		file, err := os.Create(fileName)
+func (s *CsvStore) LastId() int {
+	return s.index.lastId
+}
 
-		if err != nil {
-			panic("Error creating file: " + err.Error())
-		}
-		defer closeFile(file)
+func (s *CsvStore) Find(query models.Query) models.Expenses {
+	return s.index.between(query.From, query.To).Find(query)
+}
 
-		writer := csv.NewWriter(file)
-		defer writer.Flush()
+func (s *CsvStore) Summary() {
+	models.PrintCashFlow(*s.Expenses, "")
+}
 
-		writeHeader(writer)
-	}
+func (s *CsvStore) SummaryForMonth(month time.Month) {
+	models.PrintCashFlow(s.index.inMonth(month, time.Now().Year()), "")
+}
+
+func (s *CsvStore) SummaryGroupedBy(groupBy models.GroupBy) {
+	models.PrintPeriodSummaries(s.Expenses.GroupBy(groupBy))
 }
 
 func closeFile(file *os.File) {
@@ -170,112 +199,269 @@ func closeFile(file *os.File) {
 	}
 }
 
-func writeHeader(writer *csv.Writer) {
-	headers := strings.Split(models.HeaderFormat, "|")
-	headers = headers[1 : len(headers)-1]
-	err := writer.Write(headers)
+func (s *CsvStore) load() error {
+	records, err := readCsv(s.filename, s.codec)
 	if err != nil {
-		panic("Error writing headers: " + err.Error())
+		return err
+	}
+	if records == nil {
+		s.Reindex()
+		return s.save()
 	}
-}
 
-func (s *csvStore) load() error {
-	file, err := os.OpenFile(s.filename, os.O_RDONLY|os.O_CREATE, 0644)
+	// remove headers
+	records = records[1:]
+
+	s.head, err = readHead(s.filename)
 	if err != nil {
 		return err
 	}
+	if _, err := verifyChain(records, s.head); err != nil {
+		s.altered = fmt.Errorf("%s was altered, refusing to save over it: %w", s.filename, err)
+	}
 
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+	expenses, trash, err := fromRecords(records)
 	if err != nil {
 		return err
 	}
+	*s.Expenses = expenses
+	*s.Trash = trash
+	s.Reindex()
 
-	// remove headers
-	records = records[1:]
-
-	// reset expenses
-	*s.Expenses = []*models.Expense{}
+	s.sequence, err = readSequence(s.filename)
+	if err != nil {
+		return err
+	}
+	s.index.lastId = max(s.index.lastId, s.sequence)
+	return nil
+}
 
+// fromRecords reads the rows of the ledger, headers removed, into the
+// expenses and the trashed expenses.
+func fromRecords(records [][]string) (models.Expenses, models.Expenses, error) {
+	expenses := models.Expenses{}
+	trash := models.Expenses{}
 	for _, record := range records {
-		id, err := strconv.Atoi(record[0])
+		expense, err := fromRecord(record)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
-		amount, err := strconv.Atoi(record[2])
-		if err != nil {
-			return err
-		}
-		createdAt, err := time.Parse(models.DateFormat, record[3])
-		if err != nil {
-			return err
-		}
-		var updatedAt time.Time
-		if record[4] != "" {
-			updatedAt, err = time.Parse(models.DateFormat, record[4])
-			if err != nil {
-				return err
-			}
+		if expense.DeletedAt != nil {
+			trash = append(trash, expense)
+			continue
 		}
+		expenses = append(expenses, expense)
+	}
+	return expenses, trash, nil
+}
+
+func (s *CsvStore) save() error {
+	if s.altered != nil {
+		return s.altered
+	}
+	if err := s.backup(); err != nil {
+		return err
+	}
+	return s.write()
+}
 
-		if record[4] == "" {
-			expense := models.Expense{
-				Id:          id,
-				Amount:      amount,
-				Description: record[1],
-				CreatedAt:   createdAt,
-				UpdatedAt:   nil,
-			}
-			*s.Expenses = append(*s.Expenses, &expense)
-		} else {
-			expense := models.Expense{
-				Id:          id,
-				Amount:      amount,
-				Description: record[1],
-				CreatedAt:   createdAt,
-				UpdatedAt:   &updatedAt,
-			}
-			*s.Expenses = append(*s.Expenses, &expense)
+func (s *CsvStore) write() error {
+	var records [][]string
+	for _, expense := range slices.Concat(*s.Expenses, *s.Trash) {
+		records = append(records, toRecord(expense))
+	}
+	sealed := seal(records)
+	if err := writeCsv(s.filename, s.codec, slices.Concat([][]string{ledgerHeaders}, sealed)); err != nil {
+		return err
+	}
+	head := headOf(sealed)
+	if err := writeHead(s.filename, head); err != nil {
+		return err
+	}
+	s.head = &head
+
+	if s.index.lastId > s.sequence {
+		if err := writeSequence(s.filename, s.index.lastId); err != nil {
+			return err
 		}
+		s.sequence = s.index.lastId
 	}
 	return nil
 }
 
-func (s *csvStore) save() error {
-	file, err := os.OpenFile(s.filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-	// os.file
-	if err != nil {
+// Encrypt writes the file and its backups with the codec of the store.
+func (s *CsvStore) Encrypt() error {
+	if s.codec == nil {
+		return fmt.Errorf("no passphrase or key file to encrypt %s with", s.filename)
+	}
+	return s.rewrite(s.codec)
+}
+
+// Decrypt writes the file and its backups back in plain text.
+func (s *CsvStore) Decrypt() error {
+	return s.rewrite(nil)
+}
+
+// rewrite writes the file and its backups with the codec. The file is not
+// backed up first, which would keep a copy of it in its former form.
+func (s *CsvStore) rewrite(codec models.Codec) error {
+	if s.altered != nil {
+		return s.altered
+	}
+	if err := s.recodeBackups(s.codec, codec); err != nil {
 		return err
 	}
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	s.codec = codec
+	return s.write()
+}
 
-	writeHeader(writer)
+// Verify walks the file and reports the first broken link of the chain of
+// hashes, returning the number of entries verified.
+func (s *CsvStore) Verify() (int, error) {
+	records, err := readCsv(s.filename, s.codec)
+	if err != nil {
+		return 0, err
+	}
+	head, err := readHead(s.filename)
+	if err != nil {
+		return 0, err
+	}
+	if len(records) == 0 {
+		records = [][]string{ledgerHeaders}
+	}
+	if head == nil && !sealed(records[1:]) && len(records) > 1 {
+		return 0, fmt.Errorf("%s has no hashes yet, they are written on the next change", s.filename)
+	}
+	return verifyChain(records[1:], head)
+}
 
-	var records [][]string
-	for _, expense := range *s.Expenses {
-		if expense.UpdatedAt == nil {
-			records = append(records, []string{
-				strconv.Itoa(expense.Id),
-				expense.Description,
-				strconv.Itoa(expense.Amount),
-				expense.CreatedAt.Format(models.DateFormat),
-				"",
-			})
-			continue
-		}
+// Head is the hash the chain of the file ends on, to be compared with one
+// noted down earlier.
+func (s *CsvStore) Head() string {
+	if s.head == nil {
+		return ""
+	}
+	return s.head.Hash
+}
 
-		records = append(records, []string{
-			strconv.Itoa(expense.Id),
-			expense.Description,
-			strconv.Itoa(expense.Amount),
-			expense.CreatedAt.Format(models.DateFormat),
-			expense.UpdatedAt.Format(models.DateFormat),
-		})
+func fromRecord(record []string) (*models.Expense, error) {
+	if len(record) < 5 {
+		return nil, fmt.Errorf("invalid record %v", record)
 	}
-	err = writer.WriteAll(records)
+	id, err := strconv.Atoi(record[0])
 	if err != nil {
-		return err
+		return nil, err
+	}
+	amount, err := models.ParseAmount(record[2])
+	if err != nil {
+		return nil, err
+	}
+	createdAt, err := time.Parse(models.DateFormat, record[3])
+	if err != nil {
+		return nil, err
+	}
+
+	expense := &models.Expense{
+		Id:          id,
+		Amount:      amount,
+		Description: record[1],
+		CreatedAt:   createdAt,
+	}
+
+	if record[4] != "" {
+		updatedAt, err := time.Parse(models.DateFormat, record[4])
+		if err != nil {
+			return nil, err
+		}
+		expense.UpdatedAt = &updatedAt
+	}
+
+	if len(record) > 5 {
+		expense.Category = record[5]
+	}
+	if len(record) > 6 {
+		expense.Currency = record[6]
+	}
+	expense.Kind = models.KindExpense
+	if len(record) > 7 && record[7] != "" {
+		expense.Kind = models.Kind(record[7])
+	}
+	if len(record) > 8 && record[8] != "" {
+		expense.RefundOf, err = strconv.Atoi(record[8])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(record) > 10 {
+		expense.Account = record[9]
+		expense.ToAccount = record[10]
+	}
+	if len(record) > 12 && record[12] != "" {
+		expense.PaidBy = record[11]
+		expense.Split, err = models.ParseSplit(record[12], amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(record) > 13 && record[13] != "" {
+		expense.Recurring, err = strconv.ParseBool(record[13])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(record) > 14 && record[14] != "" {
+		deletedAt, err := parseDeletedAt(record[14])
+		if err != nil {
+			return nil, err
+		}
+		expense.DeletedAt = &deletedAt
+	}
+
+	return expense, nil
+}
+
+// parseDeletedAt reads the time an expense was moved to the trash, kept to
+// the second so that emptying the trash after some days is exact. Files
+// written before kept the date only.
+func parseDeletedAt(value string) (time.Time, error) {
+	if deletedAt, err := time.Parse(time.RFC3339, value); err == nil {
+		return deletedAt, nil
+	}
+	return time.Parse(models.DateFormat, value)
+}
+
+func toRecord(expense *models.Expense) []string {
+	updatedAt := ""
+	if expense.UpdatedAt != nil {
+		updatedAt = expense.UpdatedAt.Format(models.DateFormat)
+	}
+	refundOf := ""
+	if expense.RefundOf != 0 {
+		refundOf = strconv.Itoa(expense.RefundOf)
+	}
+	recurring := ""
+	if expense.Recurring {
+		recurring = strconv.FormatBool(expense.Recurring)
+	}
+	deletedAt := ""
+	if expense.DeletedAt != nil {
+		deletedAt = expense.DeletedAt.Format(time.RFC3339)
+	}
+	return []string{
+		strconv.Itoa(expense.Id),
+		expense.Description,
+		models.FormatAmount(expense.Amount),
+		expense.CreatedAt.Format(models.DateFormat),
+		updatedAt,
+		expense.Category,
+		expense.Currency,
+		string(expense.Kind),
+		refundOf,
+		expense.Account,
+		expense.ToAccount,
+		expense.PaidBy,
+		expense.Split.String(),
+		recurring,
+		deletedAt,
 	}
-	return nil
 }
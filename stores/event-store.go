@@ -0,0 +1,282 @@
+package stores
+
+import (
+	"expense-tracker/models"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+type Event string
+
+const (
+	EventAdded    Event = "added"
+	EventUpdated  Event = "updated"
+	EventDeleted  Event = "deleted"
+	EventRestored Event = "restored"
+	EventPurged   Event = "purged"
+
+	// SnapshotInterval is the number of events appended between snapshots.
+	SnapshotInterval = 100
+)
+
+// EventStore appends every change as an immutable event to a log file and
+// rebuilds the expenses on load by replaying the log from the last snapshot.
+// Reads are served from memory.
+type EventStore struct {
+	*InMemoryStore
+	filename         string
+	sequence         int
+	snapshotSequence int
+	fileOptions
+}
+
+var eventHeaders = slices.Concat([]string{"Sequence", "Event", "At"}, csvHeaders)
+
+func NewEventStore(filename string, options ...FileOption) models.Store {
+	store := &EventStore{
+		InMemoryStore: NewInMemoryStore().(*InMemoryStore),
+		filename:      filename,
+		fileOptions:   newFileOptions(options),
+	}
+
+	err := store.load()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return store
+}
+
+func (s *EventStore) Add(expense models.Expense) error {
+	if err := s.InMemoryStore.Add(expense); err != nil {
+		return err
+	}
+	// the added expense is the last one inserted
+	return s.append(EventAdded, (*s.Expenses)[len(*s.Expenses)-1])
+}
+
+func (s *EventStore) Update(expense models.Expense) error {
+	if err := s.InMemoryStore.Update(expense); err != nil {
+		return err
+	}
+
+	updated, err := s.Get(expense.Id)
+	if err != nil {
+		return err
+	}
+	return s.append(EventUpdated, updated)
+}
+
+func (s *EventStore) Delete(id int) error {
+	if err := s.InMemoryStore.Delete(id); err != nil {
+		return err
+	}
+	return s.append(EventDeleted, (*s.Trash)[len(*s.Trash)-1])
+}
+
+func (s *EventStore) Restore(id int) error {
+	if err := s.InMemoryStore.Restore(id); err != nil {
+		return err
+	}
+	return s.append(EventRestored, (*s.Expenses)[len(*s.Expenses)-1])
+}
+
+func (s *EventStore) EmptyTrash(deletedBefore time.Time) (int, error) {
+	purged := models.Expenses{}
+	for _, item := range *s.Trash {
+		if item.DeletedAt.Before(deletedBefore) {
+			purged = append(purged, item)
+		}
+	}
+
+	emptied, err := s.InMemoryStore.EmptyTrash(deletedBefore)
+	if err != nil || emptied == 0 {
+		return emptied, err
+	}
+	return emptied, s.append(EventPurged, purged...)
+}
+
+// Compact writes a snapshot of the expenses and starts the log over.
+func (s *EventStore) Compact() error {
+	if err := s.snapshot(); err != nil {
+		return err
+	}
+	return writeCsv(s.filename, s.codec, [][]string{eventHeaders})
+}
+
+// Encrypt writes the log and the snapshot with the codec of the store.
+func (s *EventStore) Encrypt() error {
+	if s.codec == nil {
+		return fmt.Errorf("no passphrase or key file to encrypt %s with", s.filename)
+	}
+	return s.rewrite(s.codec)
+}
+
+// Decrypt writes the log and the snapshot back in plain text.
+func (s *EventStore) Decrypt() error {
+	if err := s.rewrite(nil); err != nil {
+		return err
+	}
+	s.codec = nil
+	return nil
+}
+
+func (s *EventStore) rewrite(codec models.Codec) error {
+	for _, filename := range []string{s.filename, s.snapshotFilename()} {
+		content, err := readFile(filename, s.codec)
+		if err != nil {
+			return err
+		}
+		if content == nil {
+			continue
+		}
+		if err := writeFile(filename, codec, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply replays an event over the expenses in memory.
+func (s *EventStore) apply(event Event, expense *models.Expense) error {
+	switch event {
+	case EventAdded:
+		*s.Expenses = append(*s.Expenses, expense)
+	case EventUpdated:
+		i := slices.IndexFunc(*s.Expenses, func(item *models.Expense) bool { return item.Id == expense.Id })
+		if i < 0 {
+			return fmt.Errorf("updated expense with ID %d not found", expense.Id)
+		}
+		(*s.Expenses)[i] = expense
+	case EventDeleted:
+		if _, found := s.Expenses.Take(expense.Id); !found {
+			return fmt.Errorf("deleted expense with ID %d not found", expense.Id)
+		}
+		*s.Trash = append(*s.Trash, expense)
+	case EventRestored:
+		if _, found := s.Trash.Take(expense.Id); !found {
+			return fmt.Errorf("restored expense with ID %d not found in trash", expense.Id)
+		}
+		*s.Expenses = append(*s.Expenses, expense)
+	case EventPurged:
+		if _, found := s.Trash.Take(expense.Id); !found {
+			return fmt.Errorf("purged expense with ID %d not found in trash", expense.Id)
+		}
+	default:
+		return fmt.Errorf("unknown event %q", event)
+	}
+	return nil
+}
+
+// append writes the events to the end of the log, taking a snapshot every
+// SnapshotInterval events so loading does not replay the whole log.
+func (s *EventStore) append(event Event, expenses ...*models.Expense) error {
+	at := time.Now().Format(time.RFC3339)
+	records := [][]string{}
+	for _, expense := range expenses {
+		s.sequence++
+		records = append(records, slices.Concat([]string{strconv.Itoa(s.sequence), string(event), at}, toRecord(expense)))
+	}
+	if err := appendCsv(s.filename, s.codec, eventHeaders, records); err != nil {
+		return err
+	}
+
+	if s.sequence-s.snapshotSequence >= SnapshotInterval {
+		return s.snapshot()
+	}
+	return nil
+}
+
+// snapshot writes the expenses as of the last event, whose sequence is on
+// the first line of the file with the last ID given, before the headers.
+func (s *EventStore) snapshot() error {
+	records := [][]string{{"Sequence", strconv.Itoa(s.sequence), "Last ID", strconv.Itoa(s.index.lastId)}, csvHeaders}
+	for _, expense := range slices.Concat(*s.Expenses, *s.Trash) {
+		records = append(records, toRecord(expense))
+	}
+	if err := writeCsv(s.snapshotFilename(), s.codec, records); err != nil {
+		return err
+	}
+
+	s.snapshotSequence = s.sequence
+	return nil
+}
+
+func (s *EventStore) snapshotFilename() string {
+	return s.filename + ".snapshot"
+}
+
+func (s *EventStore) load() error {
+	if err := s.loadSnapshot(); err != nil {
+		return err
+	}
+
+	events, err := readCsv(s.filename, s.codec)
+	if err != nil {
+		return err
+	}
+	// skip the headers
+	for _, record := range events[min(1, len(events)):] {
+		sequence, err := strconv.Atoi(record[0])
+		if err != nil {
+			return err
+		}
+		// skip the events already in the snapshot
+		if sequence <= s.snapshotSequence {
+			continue
+		}
+		expense, err := fromRecord(record[3:])
+		if err != nil {
+			return err
+		}
+		if err := s.apply(Event(record[1]), expense); err != nil {
+			return err
+		}
+		s.sequence = sequence
+		// purged expenses leave the log as the only trace of their ID
+		s.index.lastId = max(s.index.lastId, expense.Id)
+	}
+	s.Reindex()
+	return nil
+}
+
+func (s *EventStore) loadSnapshot() error {
+	records, err := readCsv(s.snapshotFilename(), s.codec)
+	if err != nil || records == nil {
+		return err
+	}
+	if len(records) < 2 || len(records[0]) < 2 {
+		return fmt.Errorf("invalid snapshot %s", s.snapshotFilename())
+	}
+
+	sequence, err := strconv.Atoi(records[0][1])
+	if err != nil {
+		return err
+	}
+	s.sequence = sequence
+	s.snapshotSequence = sequence
+	// snapshots written before the last ID was kept have none
+	if len(records[0]) >= 4 {
+		s.index.lastId, err = strconv.Atoi(records[0][3])
+		if err != nil {
+			return err
+		}
+	}
+
+	// skip the sequence and the headers
+	for _, record := range records[2:] {
+		expense, err := fromRecord(record)
+		if err != nil {
+			return err
+		}
+		if expense.DeletedAt != nil {
+			*s.Trash = append(*s.Trash, expense)
+		} else {
+			*s.Expenses = append(*s.Expenses, expense)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package stores
+
+import (
+	"encoding/json"
+	"expense-tracker/models"
+	"strings"
+)
+
+// FileExporter writes expenses as CSV, with the columns of the CSV store, or
+// as JSON when the name of the file ends with .json, compressed when it ends
+// with .gz.
+type FileExporter struct{}
+
+func NewFileExporter() models.Exporter {
+	return FileExporter{}
+}
+
+func (FileExporter) Export(filename string, expenses models.Expenses) error {
+	if strings.HasSuffix(strings.TrimSuffix(filename, gzipExtension), ".json") {
+		content, err := json.MarshalIndent(expenses, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeFile(filename, nil, append(content, '\n'))
+	}
+
+	records := [][]string{csvHeaders}
+	for _, expense := range expenses {
+		records = append(records, toRecord(expense))
+	}
+	return writeCsv(filename, nil, records)
+}
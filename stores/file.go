@@ -0,0 +1,277 @@
+package stores
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"expense-tracker/models"
+	"fmt"
+	"io"
+	"os"
+)
+
+type (
+	// FileOption configures how a store reads and writes its files.
+	FileOption func(*fileOptions)
+
+	fileOptions struct {
+		codec   models.Codec
+		backups *BackupPolicy
+	}
+)
+
+// WithCodec encodes the files of the store, for instance to encrypt them.
+// Files written before without the codec are still read.
+func WithCodec(codec models.Codec) FileOption {
+	return func(o *fileOptions) {
+		o.codec = codec
+	}
+}
+
+func newFileOptions(options []FileOption) fileOptions {
+	configured := fileOptions{}
+	for _, option := range options {
+		option(&configured)
+	}
+	return configured
+}
+
+// readCsv reads all the rows of a file, headers included, none when the file
+// does not exist. Rows may have fewer columns than the headers, as older
+// files were written without the trailing optional columns.
+func readCsv(filename string, codec models.Codec) ([][]string, error) {
+	content, err := readFile(filename, codec)
+	if err != nil || content == nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
+
+// errStopStream ends a stream of rows early without an error.
+var errStopStream = errors.New("stop stream")
+
+// streamCsv hands the rows of a file to the visitor one at a time, headers
+// excluded, without reading the file whole. Encoded files cannot be decoded
+// in parts and are decoded whole first. The visitor returns errStopStream to
+// stop reading.
+func streamCsv(filename string, codec models.Codec, visit func(record []string) error) error {
+	file, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer closeFile(file)
+
+	var source io.Reader = bufio.NewReader(file)
+	prefix, err := source.(*bufio.Reader).Peek(len(cipherMagic))
+	if err != nil && err != io.EOF {
+		return err
+	}
+	switch {
+	case encrypted(prefix):
+		content, err := readFile(filename, codec)
+		if err != nil {
+			return err
+		}
+		source = bytes.NewReader(content)
+	case bytes.HasPrefix(prefix, gzipMagic):
+		decompressed, err := gzip.NewReader(source)
+		if err != nil {
+			return fmt.Errorf("%s cannot be decompressed: %w", filename, err)
+		}
+		defer decompressed.Close()
+		source = decompressed
+	}
+
+	reader := csv.NewReader(source)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	if _, err := reader.Read(); err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := visit(record); err != nil {
+			if err == errStopStream {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// writeCsv replaces the content of a file with the rows. The rows are
+// written to a temporary file first, so the file is never left half written.
+func writeCsv(filename string, codec models.Codec, records [][]string) error {
+	content, err := encodeCsv(records)
+	if err != nil {
+		return err
+	}
+	return writeFile(filename, codec, content)
+}
+
+// appendCsv adds rows to the end of a file, writing the headers first when
+// the file is new.
+func appendCsv(filename string, codec models.Codec, headers []string, records [][]string) error {
+	// encoded and compressed files are rewritten as a whole
+	if codec != nil || compressed(filename) {
+		existing, err := readFile(filename, codec)
+		if err != nil {
+			return err
+		}
+		content, err := encodeCsv(withHeaders(len(existing) == 0, headers, records))
+		if err != nil {
+			return err
+		}
+		return writeFile(filename, codec, append(existing, content...))
+	}
+
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer closeFile(file)
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	content, err := encodeCsv(withHeaders(info.Size() == 0, headers, records))
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(content)
+	return err
+}
+
+func withHeaders(empty bool, headers []string, records [][]string) [][]string {
+	if !empty {
+		return records
+	}
+	return append([][]string{headers}, records...)
+}
+
+func readFile(filename string, codec models.Codec) ([]byte, error) {
+	content, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case codec != nil && codec.Encoded(content):
+		content, err = codec.Decode(content)
+		if err != nil {
+			return nil, fmt.Errorf("%s cannot be decoded: %w", filename, err)
+		}
+	case codec == nil && encrypted(content):
+		return nil, fmt.Errorf("%s is encrypted, %w", filename, ErrKeyRequired)
+	}
+
+	return decompress(filename, content)
+}
+
+func writeFile(filename string, codec models.Codec, content []byte) error {
+	if compressed(filename) {
+		packed, err := compress(content)
+		if err != nil {
+			return err
+		}
+		content = packed
+	}
+	if codec != nil {
+		encoded, err := codec.Encode(content)
+		if err != nil {
+			return err
+		}
+		content = encoded
+	}
+	return replaceFile(filename, content)
+}
+
+// replaceFile writes the content as it is to a temporary file first, so the
+// file is never left half written.
+func replaceFile(filename string, content []byte) error {
+	temporary := filename + ".tmp"
+	if err := os.WriteFile(temporary, content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(temporary, filename)
+}
+
+func encodeCsv(records [][]string) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// codedFile is a file of settings read and written whole with the codec of
+// its options.
+type codedFile struct {
+	filename string
+	fileOptions
+}
+
+func newCodedFile(filename string, options []FileOption) codedFile {
+	return codedFile{filename: filename, fileOptions: newFileOptions(options)}
+}
+
+// read returns the rows of the file, headers removed.
+func (f *codedFile) read() ([][]string, error) {
+	records, err := readCsv(f.filename, f.codec)
+	if len(records) > 0 {
+		records = records[1:]
+	}
+	return records, err
+}
+
+func (f *codedFile) write(records [][]string) error {
+	return writeCsv(f.filename, f.codec, records)
+}
+
+// Encrypt writes the file with the codec of its options.
+func (f *codedFile) Encrypt() error {
+	if f.codec == nil {
+		return fmt.Errorf("no passphrase or key file to encrypt %s with", f.filename)
+	}
+	return f.rewrite(f.codec)
+}
+
+// Decrypt writes the file back in plain text.
+func (f *codedFile) Decrypt() error {
+	if err := f.rewrite(nil); err != nil {
+		return err
+	}
+	f.codec = nil
+	return nil
+}
+
+func (f *codedFile) rewrite(codec models.Codec) error {
+	content, err := readFile(f.filename, f.codec)
+	if err != nil || content == nil {
+		return err
+	}
+	return writeFile(f.filename, codec, content)
+}
@@ -0,0 +1,50 @@
+package stores
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// files whose name ends with .gz are written compressed with gzip, before
+// being encoded when the store has a codec. Compressed content is recognized
+// when read whatever the name, as backups of compressed files have another
+// extension.
+const gzipExtension = ".gz"
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+func compressed(filename string) bool {
+	return strings.HasSuffix(filename, gzipExtension)
+}
+
+func compress(content []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(content); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// decompress reads the content of a compressed file whole. Content which is
+// not gzip is returned as it is.
+func decompress(filename string, content []byte) ([]byte, error) {
+	if !bytes.HasPrefix(content, gzipMagic) {
+		return content, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s cannot be decompressed: %w", filename, err)
+	}
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%s cannot be decompressed: %w", filename, err)
+	}
+	return decompressed, nil
+}
@@ -0,0 +1,115 @@
+package stores
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// the hash of every row of the ledger chains it to the previous one, so a row
+// altered or inserted outside of the store breaks the chain from there on.
+// The chain alone cannot tell rows removed from its end, nor a ledger whose
+// hashes were stripped, so the number of rows and the hash of the last one,
+// the head, are kept next to the ledger. Someone able to rewrite both files
+// can still forge a whole new chain: the head printed by verify is meant to be
+// noted down elsewhere and compared.
+var hashColumn = len(csvHeaders)
+
+// chainHead is the number of rows of a sealed ledger and the hash of the
+// last one.
+type chainHead struct {
+	Rows int
+	Hash string
+}
+
+var headHeaders = []string{"Rows", "Head Hash"}
+
+// seal appends to every record the hash chaining it to the previous one.
+func seal(records [][]string) [][]string {
+	previous := ""
+	sealed := make([][]string, 0, len(records))
+	for _, record := range records {
+		previous = chainHash(previous, record)
+		sealed = append(sealed, append(record, previous))
+	}
+	return sealed
+}
+
+// headOf returns the head of sealed records.
+func headOf(records [][]string) chainHead {
+	if len(records) == 0 {
+		return chainHead{}
+	}
+	return chainHead{Rows: len(records), Hash: records[len(records)-1][hashColumn]}
+}
+
+// verifyChain walks sealed records, the headers removed, and returns the
+// number of records verified. Once a head was kept, every record must be
+// sealed and the chain must end on that head. Files written before the hashes
+// were introduced have neither and are accepted as they are.
+func verifyChain(records [][]string, head *chainHead) (int, error) {
+	if head == nil && !sealed(records) {
+		return 0, nil
+	}
+
+	previous := ""
+	for i, record := range records {
+		line := i + 2
+		if len(record) <= hashColumn || record[hashColumn] == "" {
+			return i, fmt.Errorf("broken link at line %d: hash is missing", line)
+		}
+		expected := chainHash(previous, record[:hashColumn])
+		if record[hashColumn] != expected {
+			return i, fmt.Errorf("broken link at line %d (expense ID %s): hash does not match", line, record[0])
+		}
+		previous = expected
+	}
+
+	if head != nil && (head.Rows != len(records) || head.Hash != previous) {
+		return len(records), fmt.Errorf("the chain ends after %d rows instead of %d at head %s, rows were removed or added", len(records), head.Rows, head.Hash)
+	}
+	return len(records), nil
+}
+
+func sealed(records [][]string) bool {
+	for _, record := range records {
+		if len(record) > hashColumn && record[hashColumn] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func chainHash(previous string, record []string) string {
+	sum := sha256.Sum256([]byte(previous + "\x1e" + strings.Join(record, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
+
+func headFilename(filename string) string {
+	return filename + ".head"
+}
+
+// readHead returns the head kept next to the ledger, nil when it was never
+// sealed.
+func readHead(filename string) (*chainHead, error) {
+	records, err := readCsv(headFilename(filename), nil)
+	if err != nil || len(records) < 2 {
+		return nil, err
+	}
+	if len(records[1]) < 2 {
+		return nil, fmt.Errorf("invalid head in %s", headFilename(filename))
+	}
+	rows, err := strconv.Atoi(records[1][0])
+	if err != nil {
+		return nil, err
+	}
+	return &chainHead{Rows: rows, Hash: records[1][1]}, nil
+}
+
+// writeHead keeps the head of the ledger. Hashes tell nothing of the expenses
+// and it is written in plain text.
+func writeHead(filename string, head chainHead) error {
+	return writeCsv(headFilename(filename), nil, [][]string{headHeaders, {strconv.Itoa(head.Rows), head.Hash}})
+}
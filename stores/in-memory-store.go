@@ -8,108 +8,145 @@ import (
 
 type InMemoryStore struct {
 	Expenses *models.Expenses
+	Trash    *models.Expenses
+	index    *index
 }
 
 func NewInMemoryStore() models.Store {
 	return &InMemoryStore{
 		Expenses: &models.Expenses{},
+		Trash:    &models.Expenses{},
+		index:    newIndex(nil, nil),
 	}
 }
 
 func (s *InMemoryStore) Add(expense models.Expense) error {
-	expense.Id = s.assignId()
+	expense.Id = s.index.nextId()
 	expense.CreatedAt = time.Now()
+	expense.Kind = expense.TransactionKind()
 
-	if expense.Amount < 0 {
-		return fmt.Errorf("amount cannot be negative")
+	if err := expense.Validate(); err != nil {
+		return err
+	}
+	if expense.Kind == models.KindRefund {
+		if err := s.Expenses.ValidateRefund(&expense); err != nil {
+			return err
+		}
 	}
 
-	*s.Expenses = append(*s.Expenses, &expense)
+	s.insert(&expense)
 
 	return nil
 }
 
-func (s *InMemoryStore) assignId() int {
-	currentMaxId := 0
-	for _, expense := range *s.Expenses {
-		if expense.Id > currentMaxId {
-			currentMaxId = expense.Id
-		}
-	}
-	return (currentMaxId + 1)
+func (s *InMemoryStore) insert(expense *models.Expense) {
+	s.index.insert(s.Expenses, expense)
+}
+
+// Reindex rebuilds the index after the expenses were changed directly rather
+// than through the store.
+func (s *InMemoryStore) Reindex() {
+	s.index = s.index.rebuild(*s.Expenses, *s.Trash)
 }
 
 func (s *InMemoryStore) Update(expense models.Expense) error {
-	if expense.Amount < 0 {
-		return fmt.Errorf("amount cannot be negative")
+	if err := expense.Validate(); err != nil {
+		return err
 	}
-
-	for _, item := range *s.Expenses {
-		if item.Id == expense.Id {
-			item.Amount = expense.Amount
-			item.Description = expense.Description
-			updatedAt := time.Now()
-			item.UpdatedAt = &updatedAt
-			return nil
+	if expense.TransactionKind() == models.KindRefund {
+		if err := s.Expenses.ValidateRefund(&expense); err != nil {
+			return err
 		}
 	}
-	return fmt.Errorf("expense with ID %d not found", expense.Id)
-}
 
-func (s *InMemoryStore) Delete(id int) error {
-	foundItem := false
-	for i, item := range *s.Expenses {
-		if item.Id == id {
-			*s.Expenses = append((*s.Expenses)[:i], (*s.Expenses)[i+1:]...)
-			break
-		}
+	item, found := s.index.get(expense.Id)
+	if !found {
+		return fmt.Errorf("expense with ID %d not found", expense.Id)
 	}
 
-	if !foundItem {
+	s.index.remove(item)
+	item.Apply(expense)
+	s.index.add(item)
+	return nil
+}
+
+func (s *InMemoryStore) Delete(id int) error {
+	item, found := s.index.take(s.Expenses, id)
+	if !found {
 		return fmt.Errorf("expense with ID %d not found", id)
 	}
 
+	deletedAt := time.Now()
+	item.DeletedAt = &deletedAt
+	*s.Trash = append(*s.Trash, item)
+
 	return nil
 }
 
-func (s *InMemoryStore) List() {
-	fmt.Printf(models.HeaderFormat + "\n")
-	for _, expense := range *s.Expenses {
-		expense.Print()
-	}
+func (s *InMemoryStore) Trashed() models.Expenses {
+	return s.Trash.Find(models.Query{})
 }
 
-func (s *InMemoryStore) Summary() {
-	total := 0
-	for _, expense := range *s.Expenses {
-		total += expense.Amount
+func (s *InMemoryStore) Restore(id int) error {
+	item, found := s.Trash.Take(id)
+	if !found {
+		return fmt.Errorf("expense with ID %d not found in trash", id)
 	}
-	fmt.Printf("Total expenses: %d\n", total)
+
+	item.DeletedAt = nil
+	s.insert(item)
+
+	return nil
 }
 
-func (s *InMemoryStore) SummaryForMonth(month time.Month) {
-	total := 0
-	for _, expense := range *s.Expenses {
-		if expense.UpdatedAt == nil {
-			if s.isValidSummaryInput(&expense.CreatedAt, month) {
-				total += expense.Amount
-			}
+func (s *InMemoryStore) EmptyTrash(deletedBefore time.Time) (int, error) {
+	kept := models.Expenses{}
+	for _, item := range *s.Trash {
+		if item.DeletedAt.Before(deletedBefore) {
 			continue
 		}
-
-		if s.isValidSummaryInput(expense.UpdatedAt, month) {
-			total += expense.Amount
-		}
+		kept = append(kept, item)
 	}
-	fmt.Printf("Total expenses: %d\n", total)
+
+	emptied := len(*s.Trash) - len(kept)
+	*s.Trash = kept
+
+	return emptied, nil
 }
 
-func (s *InMemoryStore) isValidSummaryInput(date *time.Time, month time.Month) bool {
-	isCurrentYear := date.Year() == time.Now().Year()
-	isSameMonth := date.Month() == month
+func (s *InMemoryStore) List() {
+	s.ListBy(models.Query{})
+}
+
+func (s *InMemoryStore) ListBy(query models.Query) {
+	s.Find(query).PrintRefunded(s.Expenses.Refunded())
+}
 
-	if isSameMonth && isCurrentYear {
-		return true
+func (s *InMemoryStore) Get(id int) (*models.Expense, error) {
+	expense, found := s.index.get(id)
+	if !found {
+		return nil, fmt.Errorf("expense with ID %d not found", id)
 	}
-	return false
+	copied := *expense
+	return &copied, nil
+}
+
+func (s *InMemoryStore) LastId() int {
+	return s.index.lastId
+}
+
+func (s *InMemoryStore) Find(query models.Query) models.Expenses {
+	return s.index.between(query.From, query.To).Find(query)
+}
+
+func (s *InMemoryStore) Summary() {
+	models.PrintCashFlow(*s.Expenses, "")
+}
+
+func (s *InMemoryStore) SummaryForMonth(month time.Month) {
+	models.PrintCashFlow(s.index.inMonth(month, time.Now().Year()), "")
+}
+
+func (s *InMemoryStore) SummaryGroupedBy(groupBy models.GroupBy) {
+	models.PrintPeriodSummaries(s.Expenses.GroupBy(groupBy))
 }
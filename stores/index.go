@@ -0,0 +1,130 @@
+package stores
+
+import (
+	"expense-tracker/models"
+	"slices"
+	"sort"
+	"time"
+)
+
+// index keeps the expenses of a store by ID and in order of summary date, so
+// lookups by ID take constant time and a range of dates is found by binary
+// search. Expenses are removed from it before a change to their date and
+// added back after. It also keeps the position of every expense in the
+// expenses of the store, so one is taken out of them without a scan.
+type index struct {
+	byId      map[int]*models.Expense
+	positions map[int]int
+	byDate    models.Expenses
+	// lastId is the highest ID given, trashed expenses included
+	lastId int
+}
+
+func newIndex(expenses models.Expenses, trash models.Expenses) *index {
+	built := &index{
+		byId:      make(map[int]*models.Expense, len(expenses)),
+		positions: make(map[int]int, len(expenses)),
+		byDate:    slices.Clone(expenses),
+	}
+	for i, expense := range expenses {
+		built.byId[expense.Id] = expense
+		built.positions[expense.Id] = i
+		built.lastId = max(built.lastId, expense.Id)
+	}
+	for _, expense := range trash {
+		built.lastId = max(built.lastId, expense.Id)
+	}
+	slices.SortFunc(built.byDate, compareDates)
+	return built
+}
+
+// rebuild indexes the expenses again, keeping the IDs given so far as the
+// expenses they were given to may be gone.
+func (x *index) rebuild(expenses models.Expenses, trash models.Expenses) *index {
+	rebuilt := newIndex(expenses, trash)
+	if x != nil {
+		rebuilt.lastId = max(rebuilt.lastId, x.lastId)
+	}
+	return rebuilt
+}
+
+func (x *index) nextId() int {
+	return x.lastId + 1
+}
+
+func (x *index) get(id int) (*models.Expense, bool) {
+	expense, ok := x.byId[id]
+	return expense, ok
+}
+
+func (x *index) add(expense *models.Expense) {
+	x.byId[expense.Id] = expense
+	x.lastId = max(x.lastId, expense.Id)
+	i, _ := slices.BinarySearchFunc(x.byDate, expense, compareDates)
+	x.byDate = slices.Insert(x.byDate, i, expense)
+}
+
+func (x *index) remove(expense *models.Expense) {
+	delete(x.byId, expense.Id)
+	if i, found := slices.BinarySearchFunc(x.byDate, expense, compareDates); found {
+		x.byDate = slices.Delete(x.byDate, i, i+1)
+	}
+}
+
+// insert appends the expense to the expenses of the store and indexes it.
+func (x *index) insert(expenses *models.Expenses, expense *models.Expense) {
+	*expenses = append(*expenses, expense)
+	x.positions[expense.Id] = len(*expenses) - 1
+	x.add(expense)
+}
+
+// take removes the expense with the ID from the expenses of the store and
+// from the index. The last expense moves to its position, so the expenses of
+// the store are not kept in order.
+func (x *index) take(expenses *models.Expenses, id int) (*models.Expense, bool) {
+	i, found := x.positions[id]
+	if !found {
+		return nil, false
+	}
+	expense := (*expenses)[i]
+	last := len(*expenses) - 1
+	(*expenses)[i] = (*expenses)[last]
+	x.positions[(*expenses)[i].Id] = i
+	(*expenses)[last] = nil
+	*expenses = (*expenses)[:last]
+	delete(x.positions, id)
+
+	x.remove(expense)
+	return expense, true
+}
+
+// between returns the expenses whose summary date is from the first date
+// and before the second, zero dates leaving the range open as in queries.
+// The expenses are those of the index and must not be reordered.
+func (x *index) between(from time.Time, to time.Time) models.Expenses {
+	start, end := 0, len(x.byDate)
+	if !from.IsZero() {
+		start = sort.Search(len(x.byDate), func(i int) bool { return !x.byDate[i].SummaryDate().Before(from) })
+	}
+	if !to.IsZero() {
+		end = sort.Search(len(x.byDate), func(i int) bool { return !x.byDate[i].SummaryDate().Before(to) })
+	}
+	if start >= end {
+		return models.Expenses{}
+	}
+	return x.byDate[start:end]
+}
+
+// inMonth returns the expenses the monthly summary accounts in the month,
+// searching a day around it as their dates may be in another time zone.
+func (x *index) inMonth(month time.Month, year int) models.Expenses {
+	start := models.MonthStart(year, month)
+	return x.between(start.AddDate(0, 0, -1), start.AddDate(0, 1, 1)).InMonth(month, year)
+}
+
+func compareDates(a *models.Expense, b *models.Expense) int {
+	if order := a.SummaryDate().Compare(b.SummaryDate()); order != 0 {
+		return order
+	}
+	return a.Id - b.Id
+}
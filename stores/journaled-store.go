@@ -0,0 +1,301 @@
+package stores
+
+import (
+	"encoding/json"
+	"expense-tracker/models"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// JournaledStore records the changes made through the store it wraps in a
+// journal file, so they can be undone and redone across runs.
+type JournaledStore struct {
+	models.Store
+	operations models.Operations
+	filename   string
+	fileOptions
+}
+
+var journalHeaders = []string{"ID", "Action", "Expense ID", "At", "Undone", "Before", "After"}
+
+func NewJournaledStore(store models.Store, filename string, options ...FileOption) models.Store {
+	journaled := &JournaledStore{
+		Store:       store,
+		operations:  models.Operations{},
+		filename:    filename,
+		fileOptions: newFileOptions(options),
+	}
+
+	err := journaled.load()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return journaled
+}
+
+func (s *JournaledStore) Unwrap() models.Store {
+	return s.Store
+}
+
+func (s *JournaledStore) Add(expense models.Expense) error {
+	if err := s.Store.Add(expense); err != nil {
+		return err
+	}
+
+	return s.record(models.Operation{Action: models.ActionAdd, ExpenseId: s.Store.LastId()})
+}
+
+func (s *JournaledStore) Update(expense models.Expense) error {
+	before, err := s.Store.Get(expense.Id)
+	if err != nil {
+		return err
+	}
+	previous := *before
+
+	if err := s.Store.Update(expense); err != nil {
+		return err
+	}
+
+	after, err := s.Store.Get(expense.Id)
+	if err != nil {
+		return err
+	}
+	current := *after
+
+	return s.record(models.Operation{Action: models.ActionUpdate, ExpenseId: expense.Id, Before: &previous, After: &current})
+}
+
+func (s *JournaledStore) Delete(id int) error {
+	if err := s.Store.Delete(id); err != nil {
+		return err
+	}
+	return s.record(models.Operation{Action: models.ActionDelete, ExpenseId: id})
+}
+
+func (s *JournaledStore) Restore(id int) error {
+	if err := s.Store.Restore(id); err != nil {
+		return err
+	}
+	return s.record(models.Operation{Action: models.ActionRestore, ExpenseId: id})
+}
+
+// Undo reverses the last n operations not undone yet, latest first.
+func (s *JournaledStore) Undo(n int) (models.Operations, error) {
+	undone := models.Operations{}
+	for i := len(s.operations) - 1; i >= 0 && len(undone) < n; i-- {
+		if s.operations[i].Undone {
+			continue
+		}
+		if err := s.apply(s.operations[i].Inverse(), s.operations[i].Before, s.operations[i].ExpenseId); err != nil {
+			return undone, s.saveAfter(err)
+		}
+		s.operations[i].Undone = true
+		undone = append(undone, s.operations[i])
+	}
+
+	if len(undone) == 0 {
+		return undone, fmt.Errorf("nothing to undo")
+	}
+	return undone, s.save()
+}
+
+// Redo replays the first n undone operations, earliest first.
+func (s *JournaledStore) Redo(n int) (models.Operations, error) {
+	redone := models.Operations{}
+	for i := range s.operations {
+		if len(redone) == n {
+			break
+		}
+		if !s.operations[i].Undone {
+			continue
+		}
+		if err := s.apply(s.operations[i].Action, s.operations[i].After, s.operations[i].ExpenseId); err != nil {
+			return redone, s.saveAfter(err)
+		}
+		s.operations[i].Undone = false
+		redone = append(redone, s.operations[i])
+	}
+
+	if len(redone) == 0 {
+		return redone, fmt.Errorf("nothing to redo")
+	}
+	return redone, s.save()
+}
+
+func (s *JournaledStore) History() models.Operations {
+	return s.operations
+}
+
+// apply performs an action on the wrapped store, leaving the journal as is.
+func (s *JournaledStore) apply(action models.Action, state *models.Expense, id int) error {
+	switch action {
+	case models.ActionAdd, models.ActionRestore:
+		return s.Store.Restore(id)
+	case models.ActionDelete:
+		return s.Store.Delete(id)
+	case models.ActionUpdate:
+		return s.Store.Update(*state)
+	}
+	return fmt.Errorf("unknown action %q", action)
+}
+
+// record appends an operation, dropping the undone ones which can no longer
+// be redone and the oldest beyond the journal limit.
+func (s *JournaledStore) record(operation models.Operation) error {
+	operation.Id = 1
+	if len(s.operations) > 0 {
+		operation.Id = s.operations[len(s.operations)-1].Id + 1
+	}
+	operation.At = time.Now()
+
+	kept := models.Operations{}
+	for _, item := range s.operations {
+		if !item.Undone {
+			kept = append(kept, item)
+		}
+	}
+	kept = append(kept, operation)
+	if len(kept) > models.JournalLimit {
+		kept = kept[len(kept)-models.JournalLimit:]
+	}
+	s.operations = kept
+
+	return s.save()
+}
+
+// saveAfter keeps what was applied before an operation failed.
+func (s *JournaledStore) saveAfter(err error) error {
+	if saveErr := s.save(); saveErr != nil {
+		return saveErr
+	}
+	return err
+}
+
+func (s *JournaledStore) load() error {
+	records, err := readCsv(s.filename, s.codec)
+	if err != nil {
+		return err
+	}
+
+	// remove headers
+	if len(records) > 0 {
+		records = records[1:]
+	}
+
+	for _, record := range records {
+		operation, err := operationFromRecord(record)
+		if err != nil {
+			return err
+		}
+		s.operations = append(s.operations, operation)
+	}
+	return nil
+}
+
+func (s *JournaledStore) save() error {
+	records := [][]string{journalHeaders}
+	for _, operation := range s.operations {
+		record, err := operationToRecord(operation)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+	}
+	return writeCsv(s.filename, s.codec, records)
+}
+
+// Encrypt writes the journal with the codec of the store.
+func (s *JournaledStore) Encrypt() error {
+	if s.codec == nil {
+		return fmt.Errorf("no passphrase or key file to encrypt %s with", s.filename)
+	}
+	return s.save()
+}
+
+// Decrypt writes the journal back in plain text.
+func (s *JournaledStore) Decrypt() error {
+	s.codec = nil
+	return s.save()
+}
+
+func operationFromRecord(record []string) (models.Operation, error) {
+	id, err := strconv.Atoi(record[0])
+	if err != nil {
+		return models.Operation{}, err
+	}
+	expenseId, err := strconv.Atoi(record[2])
+	if err != nil {
+		return models.Operation{}, err
+	}
+	at, err := time.Parse(time.RFC3339, record[3])
+	if err != nil {
+		return models.Operation{}, err
+	}
+	undone, err := strconv.ParseBool(record[4])
+	if err != nil {
+		return models.Operation{}, err
+	}
+	before, err := stateFromJson(record[5])
+	if err != nil {
+		return models.Operation{}, err
+	}
+	after, err := stateFromJson(record[6])
+	if err != nil {
+		return models.Operation{}, err
+	}
+
+	return models.Operation{
+		Id:        id,
+		Action:    models.Action(record[1]),
+		ExpenseId: expenseId,
+		Before:    before,
+		After:     after,
+		At:        at,
+		Undone:    undone,
+	}, nil
+}
+
+func operationToRecord(operation models.Operation) ([]string, error) {
+	before, err := stateToJson(operation.Before)
+	if err != nil {
+		return nil, err
+	}
+	after, err := stateToJson(operation.After)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{
+		strconv.Itoa(operation.Id),
+		string(operation.Action),
+		strconv.Itoa(operation.ExpenseId),
+		operation.At.Format(time.RFC3339),
+		strconv.FormatBool(operation.Undone),
+		before,
+		after,
+	}, nil
+}
+
+// the states of updated expenses are kept as JSON so the journal does not
+// depend on the columns of the expenses file
+func stateFromJson(value string) (*models.Expense, error) {
+	if value == "" {
+		return nil, nil
+	}
+	expense := &models.Expense{}
+	if err := json.Unmarshal([]byte(value), expense); err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
+func stateToJson(expense *models.Expense) (string, error) {
+	if expense == nil {
+		return "", nil
+	}
+	value, err := json.Marshal(expense)
+	return string(value), err
+}
@@ -0,0 +1,471 @@
+package stores
+
+import (
+	"expense-tracker/models"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PartitionedStore keeps the expenses in one ledger file per year they were
+// created in, under a directory, and loads only the years a change or a query
+// needs. A manifest holds the range of IDs and the latest date of every year,
+// so IDs stay unique across the years and queries find their years without
+// reading them.
+type PartitionedStore struct {
+	directory  string
+	manifest   map[int]partitionEntry
+	partitions map[int]*CsvStore
+	fileOptions
+}
+
+type partitionEntry struct {
+	Year     int
+	FirstId  int
+	LastId   int
+	LastDate time.Time
+}
+
+var partitionHeaders = []string{"Year", "First ID", "Last ID", "Last Date"}
+
+const manifestFilename = "partitions.csv"
+
+func NewPartitionedStore(directory string, options ...FileOption) models.Store {
+	store := &PartitionedStore{
+		directory:   directory,
+		manifest:    map[int]partitionEntry{},
+		partitions:  map[int]*CsvStore{},
+		fileOptions: newFileOptions(options),
+	}
+	// the years are not backed up, the store does not list nor restore
+	// backups
+	store.backups = nil
+
+	err := store.load()
+
+	if err != nil {
+		panic(err)
+	}
+
+	return store
+}
+
+func (s *PartitionedStore) Add(expense models.Expense) error {
+	expense.Id = s.nextId()
+	expense.CreatedAt = time.Now()
+	expense.Kind = expense.TransactionKind()
+
+	if err := expense.Validate(); err != nil {
+		return err
+	}
+	if expense.Kind == models.KindRefund {
+		if err := s.validateRefund(&expense); err != nil {
+			return err
+		}
+	}
+
+	year := expense.CreatedAt.Year()
+	partition, err := s.partition(year)
+	if err != nil {
+		return err
+	}
+
+	partition.insert(&expense)
+	if err := partition.save(); err != nil {
+		return err
+	}
+	return s.index(year)
+}
+
+func (s *PartitionedStore) Update(expense models.Expense) error {
+	if err := expense.Validate(); err != nil {
+		return err
+	}
+	if expense.TransactionKind() == models.KindRefund {
+		if err := s.validateRefund(&expense); err != nil {
+			return err
+		}
+	}
+
+	for _, year := range s.containing(expense.Id) {
+		partition, err := s.partition(year)
+		if err != nil {
+			return err
+		}
+		if partition.apply(expense) {
+			if err := partition.save(); err != nil {
+				return err
+			}
+			return s.index(year)
+		}
+	}
+
+	return fmt.Errorf("expense with ID %d not found", expense.Id)
+}
+
+func (s *PartitionedStore) Delete(id int) error {
+	for _, year := range s.containing(id) {
+		partition, err := s.partition(year)
+		if err != nil {
+			return err
+		}
+		if _, err := partition.Get(id); err == nil {
+			if err := partition.Delete(id); err != nil {
+				return err
+			}
+			return s.index(year)
+		}
+	}
+
+	return fmt.Errorf("expense with ID %d not found", id)
+}
+
+func (s *PartitionedStore) Trashed() models.Expenses {
+	_, trash := s.mustLoad(s.years(nil))
+	return trash.Find(models.Query{})
+}
+
+func (s *PartitionedStore) Restore(id int) error {
+	for _, year := range s.containing(id) {
+		partition, err := s.partition(year)
+		if err != nil {
+			return err
+		}
+		if slices.ContainsFunc(*partition.Trash, func(item *models.Expense) bool { return item.Id == id }) {
+			if err := partition.Restore(id); err != nil {
+				return err
+			}
+			return s.index(year)
+		}
+	}
+
+	return fmt.Errorf("expense with ID %d not found in trash", id)
+}
+
+func (s *PartitionedStore) EmptyTrash(deletedBefore time.Time) (int, error) {
+	emptied := 0
+	for _, year := range s.years(nil) {
+		partition, err := s.partition(year)
+		if err != nil {
+			return emptied, err
+		}
+		count, err := partition.EmptyTrash(deletedBefore)
+		emptied += count
+		if err != nil {
+			return emptied, err
+		}
+		if err := s.index(year); err != nil {
+			return emptied, err
+		}
+	}
+	return emptied, nil
+}
+
+func (s *PartitionedStore) List() {
+	s.ListBy(models.Query{})
+}
+
+// ListBy also loads the years after the ones of the query, for the refunds
+// of the expenses listed.
+func (s *PartitionedStore) ListBy(query models.Query) {
+	years := s.yearsFor(query)
+	if len(years) > 0 {
+		years = s.years(func(entry partitionEntry) bool { return entry.Year >= years[0] })
+	}
+
+	expenses, _ := s.mustLoad(years)
+	expenses.Find(query).PrintRefunded(expenses.Refunded())
+}
+
+func (s *PartitionedStore) Find(query models.Query) models.Expenses {
+	expenses, _ := s.mustLoad(s.yearsFor(query))
+	return expenses.Find(query)
+}
+
+func (s *PartitionedStore) Get(id int) (*models.Expense, error) {
+	for _, year := range s.containing(id) {
+		partition, err := s.partition(year)
+		if err != nil {
+			return nil, err
+		}
+		if expense, err := partition.Get(id); err == nil {
+			return expense, nil
+		}
+	}
+	return nil, fmt.Errorf("expense with ID %d not found", id)
+}
+
+func (s *PartitionedStore) Summary() {
+	expenses, _ := s.mustLoad(s.years(nil))
+	models.PrintCashFlow(expenses, "")
+}
+
+func (s *PartitionedStore) SummaryForMonth(month time.Month) {
+	year := time.Now().Year()
+	expenses, _ := s.mustLoad(s.yearsFor(models.Query{}.ForMonth(month, year)))
+	models.PrintCashFlow(expenses.InMonth(month, year), "")
+}
+
+func (s *PartitionedStore) SummaryGroupedBy(groupBy models.GroupBy) {
+	expenses, _ := s.mustLoad(s.years(nil))
+	models.PrintPeriodSummaries(expenses.GroupBy(groupBy))
+}
+
+// Verify walks the chain of hashes of every year.
+func (s *PartitionedStore) Verify() (int, error) {
+	verified := 0
+	for _, year := range s.years(nil) {
+		partition, err := s.partition(year)
+		if err != nil {
+			return verified, err
+		}
+		count, err := partition.Verify()
+		verified += count
+		if err != nil {
+			return verified, fmt.Errorf("%s: %w", partition.filename, err)
+		}
+	}
+	return verified, nil
+}
+
+// Head chains the heads of the years, oldest first.
+func (s *PartitionedStore) Head() string {
+	head := ""
+	for _, year := range s.years(nil) {
+		partition, err := s.partition(year)
+		if err != nil {
+			panic(err)
+		}
+		head = chainHash(head, []string{strconv.Itoa(year), partition.Head()})
+	}
+	return head
+}
+
+// Encrypt writes every year and the manifest with the codec of the store.
+func (s *PartitionedStore) Encrypt() error {
+	if s.codec == nil {
+		return fmt.Errorf("no passphrase or key file to encrypt %s with", s.directory)
+	}
+	return s.rewrite(s.codec)
+}
+
+// Decrypt writes every year and the manifest back in plain text.
+func (s *PartitionedStore) Decrypt() error {
+	return s.rewrite(nil)
+}
+
+func (s *PartitionedStore) rewrite(codec models.Codec) error {
+	for _, year := range s.years(nil) {
+		partition, err := s.partition(year)
+		if err != nil {
+			return err
+		}
+		if err := partition.rewrite(codec); err != nil {
+			return err
+		}
+	}
+	s.codec = codec
+	return s.saveManifest()
+}
+
+func (s *PartitionedStore) LastId() int {
+	lastId := 0
+	for _, entry := range s.manifest {
+		lastId = max(lastId, entry.LastId)
+	}
+	return lastId
+}
+
+func (s *PartitionedStore) nextId() int {
+	return s.LastId() + 1
+}
+
+// validateRefund loads the year of the refunded expense and the years after,
+// where its other refunds are.
+func (s *PartitionedStore) validateRefund(refund *models.Expense) error {
+	expenses, _, err := s.loadYears(s.years(func(entry partitionEntry) bool {
+		return entry.LastId >= refund.RefundOf
+	}))
+	if err != nil {
+		return err
+	}
+	return expenses.ValidateRefund(refund)
+}
+
+// yearsFor returns the years with expenses the query can match, by the date
+// used for summaries.
+func (s *PartitionedStore) yearsFor(query models.Query) []int {
+	return s.years(func(entry partitionEntry) bool {
+		startsBefore := query.To.IsZero() || models.MonthStart(entry.Year, time.January).Before(query.To)
+		endsAfter := query.From.IsZero() || !entry.LastDate.Before(query.From)
+		return startsBefore && endsAfter
+	})
+}
+
+// containing returns the years whose range of IDs holds the ID.
+func (s *PartitionedStore) containing(id int) []int {
+	return s.years(func(entry partitionEntry) bool {
+		return entry.FirstId <= id && id <= entry.LastId
+	})
+}
+
+// years returns the years of the manifest matching the filter, all of them
+// when it is nil, in order.
+func (s *PartitionedStore) years(filter func(entry partitionEntry) bool) []int {
+	years := []int{}
+	for year, entry := range s.manifest {
+		if filter == nil || filter(entry) {
+			years = append(years, year)
+		}
+	}
+	slices.Sort(years)
+	return years
+}
+
+func (s *PartitionedStore) mustLoad(years []int) (models.Expenses, models.Expenses) {
+	expenses, trash, err := s.loadYears(years)
+	if err != nil {
+		panic(err)
+	}
+	return expenses, trash
+}
+
+func (s *PartitionedStore) loadYears(years []int) (models.Expenses, models.Expenses, error) {
+	expenses := models.Expenses{}
+	trash := models.Expenses{}
+	for _, year := range years {
+		partition, err := s.partition(year)
+		if err != nil {
+			return nil, nil, err
+		}
+		expenses = append(expenses, *partition.Expenses...)
+		trash = append(trash, *partition.Trash...)
+	}
+	return expenses, trash, nil
+}
+
+// partition loads the ledger of a year the first time it is needed.
+func (s *PartitionedStore) partition(year int) (*CsvStore, error) {
+	if partition, ok := s.partitions[year]; ok {
+		return partition, nil
+	}
+
+	partition := &CsvStore{
+		Expenses:    &models.Expenses{},
+		Trash:       &models.Expenses{},
+		filename:    filepath.Join(s.directory, strconv.Itoa(year)+".csv"),
+		fileOptions: s.fileOptions,
+	}
+	if err := partition.load(); err != nil {
+		return nil, err
+	}
+
+	s.partitions[year] = partition
+	return partition, nil
+}
+
+// index updates the manifest entry of a year after it changed.
+func (s *PartitionedStore) index(year int) error {
+	partition, err := s.partition(year)
+	if err != nil {
+		return err
+	}
+
+	// the last ID given stays in the manifest once its expense is purged,
+	// so it is not given again
+	lastId := partition.index.lastId
+	if lastId == 0 {
+		delete(s.manifest, year)
+		return s.saveManifest()
+	}
+
+	entry := partitionEntry{Year: year, FirstId: lastId, LastId: lastId}
+	for _, expense := range slices.Concat(*partition.Expenses, *partition.Trash) {
+		entry.FirstId = min(entry.FirstId, expense.Id)
+		entry.LastId = max(entry.LastId, expense.Id)
+		if expense.SummaryDate().After(entry.LastDate) {
+			entry.LastDate = expense.SummaryDate()
+		}
+	}
+	s.manifest[year] = entry
+	return s.saveManifest()
+}
+
+// load reads the manifest, indexing the years found in the directory which
+// are missing from it.
+func (s *PartitionedStore) load() error {
+	if err := os.MkdirAll(s.directory, 0755); err != nil {
+		return err
+	}
+
+	records, err := readCsv(filepath.Join(s.directory, manifestFilename), s.codec)
+	if err != nil {
+		return err
+	}
+	for _, record := range records[min(1, len(records)):] {
+		entry, err := partitionEntryFromRecord(record)
+		if err != nil {
+			return err
+		}
+		s.manifest[entry.Year] = entry
+	}
+
+	files, err := filepath.Glob(filepath.Join(s.directory, "[0-9][0-9][0-9][0-9].csv"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		year, err := strconv.Atoi(strings.TrimSuffix(filepath.Base(file), ".csv"))
+		if err != nil {
+			return err
+		}
+		if _, ok := s.manifest[year]; ok {
+			continue
+		}
+		if err := s.index(year); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PartitionedStore) saveManifest() error {
+	records := [][]string{partitionHeaders}
+	for _, year := range s.years(nil) {
+		entry := s.manifest[year]
+		records = append(records, []string{
+			strconv.Itoa(entry.Year),
+			strconv.Itoa(entry.FirstId),
+			strconv.Itoa(entry.LastId),
+			entry.LastDate.Format(time.RFC3339),
+		})
+	}
+	return writeCsv(filepath.Join(s.directory, manifestFilename), s.codec, records)
+}
+
+func partitionEntryFromRecord(record []string) (partitionEntry, error) {
+	if len(record) < len(partitionHeaders) {
+		return partitionEntry{}, fmt.Errorf("invalid partition %v", record)
+	}
+	year, err := strconv.Atoi(record[0])
+	if err != nil {
+		return partitionEntry{}, err
+	}
+	firstId, err := strconv.Atoi(record[1])
+	if err != nil {
+		return partitionEntry{}, err
+	}
+	lastId, err := strconv.Atoi(record[2])
+	if err != nil {
+		return partitionEntry{}, err
+	}
+	lastDate, err := time.Parse(time.RFC3339, record[3])
+	if err != nil {
+		return partitionEntry{}, err
+	}
+	return partitionEntry{Year: year, FirstId: firstId, LastId: lastId, LastDate: lastDate}, nil
+}
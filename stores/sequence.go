@@ -0,0 +1,33 @@
+package stores
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// the last ID given by a store is kept next to its file, so the ID of an
+// expense purged from the trash is never given again. Files written before
+// have none and start from their highest ID.
+var sequenceHeaders = []string{"Last ID"}
+
+func sequenceFilename(filename string) string {
+	return filename + ".sequence"
+}
+
+// readSequence returns the last ID given, zero when none was kept yet.
+func readSequence(filename string) (int, error) {
+	records, err := readCsv(sequenceFilename(filename), nil)
+	if err != nil || len(records) < 2 {
+		return 0, err
+	}
+	if len(records[1]) == 0 {
+		return 0, fmt.Errorf("invalid sequence in %s", sequenceFilename(filename))
+	}
+	return strconv.Atoi(records[1][0])
+}
+
+// writeSequence keeps the last ID given. It holds nothing of the expenses and
+// is written in plain text.
+func writeSequence(filename string, lastId int) error {
+	return writeCsv(sequenceFilename(filename), nil, [][]string{sequenceHeaders, {strconv.Itoa(lastId)}})
+}
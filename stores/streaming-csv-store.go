@@ -0,0 +1,226 @@
+package stores
+
+import (
+	"expense-tracker/models"
+	"fmt"
+	"time"
+)
+
+// StreamingCsvStore answers queries by reading the ledger of the CSV store
+// row by row, aggregating as it goes, so summaries and filtered lists over a
+// long history never hold it whole in memory. Changes, verification, backups
+// and encryption are made through a CsvStore over the same file, loaded the
+// first time one is needed. It does not unwrap to that store, so looking for
+// an optional interface along the chain never loads it.
+type StreamingCsvStore struct {
+	filename string
+	options  []FileOption
+	fileOptions
+	ledger *CsvStore
+}
+
+func NewStreamingCsvStore(filename string, options ...FileOption) models.Store {
+	return &StreamingCsvStore{
+		filename:    filename,
+		options:     options,
+		fileOptions: newFileOptions(options),
+	}
+}
+
+func (s *StreamingCsvStore) Add(expense models.Expense) error {
+	return s.csvStore().Add(expense)
+}
+
+func (s *StreamingCsvStore) Update(expense models.Expense) error {
+	return s.csvStore().Update(expense)
+}
+
+func (s *StreamingCsvStore) Delete(id int) error {
+	return s.csvStore().Delete(id)
+}
+
+func (s *StreamingCsvStore) Restore(id int) error {
+	return s.csvStore().Restore(id)
+}
+
+func (s *StreamingCsvStore) EmptyTrash(deletedBefore time.Time) (int, error) {
+	return s.csvStore().EmptyTrash(deletedBefore)
+}
+
+// csvStore returns the CsvStore changes are made through, loading it the
+// first time.
+func (s *StreamingCsvStore) csvStore() *CsvStore {
+	if s.ledger == nil {
+		s.ledger = NewCsvStore(s.filename, s.options...).(*CsvStore)
+	}
+	return s.ledger
+}
+
+func (s *StreamingCsvStore) Verify() (int, error) {
+	return s.csvStore().Verify()
+}
+
+func (s *StreamingCsvStore) Head() string {
+	return s.csvStore().Head()
+}
+
+// Encrypt rewrites the file encrypted, which is then streamed with the codec.
+func (s *StreamingCsvStore) Encrypt() error {
+	if err := s.csvStore().Encrypt(); err != nil {
+		return err
+	}
+	s.codec = s.ledger.codec
+	return nil
+}
+
+// Decrypt rewrites the file in plain text, which is then streamed as it is.
+func (s *StreamingCsvStore) Decrypt() error {
+	if err := s.csvStore().Decrypt(); err != nil {
+		return err
+	}
+	s.codec = nil
+	return nil
+}
+
+func (s *StreamingCsvStore) Backups() ([]models.Backup, error) {
+	return s.csvStore().Backups()
+}
+
+func (s *StreamingCsvStore) CreateBackup() (models.Backup, error) {
+	return s.csvStore().CreateBackup()
+}
+
+func (s *StreamingCsvStore) BackupExpenses(id string) (models.Expenses, error) {
+	return s.csvStore().BackupExpenses(id)
+}
+
+func (s *StreamingCsvStore) RestoreBackup(id string) error {
+	return s.csvStore().RestoreBackup(id)
+}
+
+func (s *StreamingCsvStore) Trashed() models.Expenses {
+	trash := models.Expenses{}
+	s.mustStream(func(expense *models.Expense) error {
+		if expense.DeletedAt != nil {
+			trash = append(trash, expense)
+		}
+		return nil
+	})
+	return trash.Find(models.Query{})
+}
+
+func (s *StreamingCsvStore) List() {
+	s.ListBy(models.Query{})
+}
+
+// ListBy keeps only the expenses matching the query, along with the refunded
+// amounts of every expense.
+func (s *StreamingCsvStore) ListBy(query models.Query) {
+	matching := models.Expenses{}
+	refunded := map[int]int{}
+	s.mustStream(func(expense *models.Expense) error {
+		if expense.DeletedAt != nil {
+			return nil
+		}
+		if expense.TransactionKind() == models.KindRefund {
+			refunded[expense.RefundOf] += expense.Amount
+		}
+		if query.Matches(expense) {
+			matching = append(matching, expense)
+		}
+		return nil
+	})
+	matching.Find(query).PrintRefunded(refunded)
+}
+
+func (s *StreamingCsvStore) Find(query models.Query) models.Expenses {
+	matching := models.Expenses{}
+	s.mustStream(func(expense *models.Expense) error {
+		if expense.DeletedAt == nil && query.Matches(expense) {
+			matching = append(matching, expense)
+		}
+		return nil
+	})
+	return matching.Find(query)
+}
+
+func (s *StreamingCsvStore) Any(query models.Query, found func(expense *models.Expense) bool) bool {
+	matched := false
+	s.mustStream(func(expense *models.Expense) error {
+		if expense.DeletedAt == nil && query.Matches(expense) && found(expense) {
+			matched = true
+			return errStopStream
+		}
+		return nil
+	})
+	return matched
+}
+
+func (s *StreamingCsvStore) Get(id int) (*models.Expense, error) {
+	var found *models.Expense
+	err := s.stream(func(expense *models.Expense) error {
+		if expense.Id == id && expense.DeletedAt == nil {
+			found = expense
+			return errStopStream
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, fmt.Errorf("expense with ID %d not found", id)
+	}
+	return found, nil
+}
+
+// LastId is asked after a change, when the ledger is loaded anyway.
+func (s *StreamingCsvStore) LastId() int {
+	return s.csvStore().LastId()
+}
+
+func (s *StreamingCsvStore) Summary() {
+	s.CashFlow(func(*models.Expense) bool { return true }).Print("")
+}
+
+func (s *StreamingCsvStore) SummaryForMonth(month time.Month) {
+	year := time.Now().Year()
+	s.CashFlow(func(expense *models.Expense) bool {
+		return models.InMonth(expense.SummaryDate(), month, year)
+	}).Print("")
+}
+
+// SummaryGroupedBy needs every expense to compare the periods, and reads
+// them all.
+func (s *StreamingCsvStore) SummaryGroupedBy(groupBy models.GroupBy) {
+	models.PrintPeriodSummaries(s.Find(models.Query{}).GroupBy(groupBy))
+}
+
+// CashFlow totals the expenses kept by the filter in a single pass over the
+// file.
+func (s *StreamingCsvStore) CashFlow(keep func(expense *models.Expense) bool) models.CashFlow {
+	cashFlow := models.CashFlow{}
+	s.mustStream(func(expense *models.Expense) error {
+		if expense.DeletedAt == nil && keep(expense) {
+			cashFlow.Add(expense)
+		}
+		return nil
+	})
+	return cashFlow
+}
+
+func (s *StreamingCsvStore) stream(visit func(expense *models.Expense) error) error {
+	return streamCsv(s.filename, s.codec, func(record []string) error {
+		expense, err := fromRecord(record)
+		if err != nil {
+			return err
+		}
+		return visit(expense)
+	})
+}
+
+func (s *StreamingCsvStore) mustStream(visit func(expense *models.Expense) error) {
+	if err := s.stream(visit); err != nil {
+		panic(err)
+	}
+}
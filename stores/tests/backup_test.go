@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"expense-tracker/stores"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackup(t *testing.T) {
+	asserts := assert.New(t)
+
+	newBackedUpStore := func(t *testing.T, count int) models.Store {
+		return stores.NewCsvStore(filepath.Join(t.TempDir(), "test.csv"), stores.WithBackups(stores.BackupPolicy{Count: count}))
+	}
+
+	t.Run("✅ should back up the ledger before every change", func(t *testing.T) {
+		// Given
+		store := newBackedUpStore(t, 10)
+
+		// When
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+
+		// Then
+		backups, err := store.(models.Backuper).Backups()
+		asserts.Nil(err)
+		asserts.Equal(2, len(backups))
+		latest, _ := store.(models.Backuper).BackupExpenses(backups[0].Id)
+		asserts.Equal(1, len(latest))
+		asserts.Equal("Lunch", latest[0].Description)
+	})
+
+	t.Run("✅ should keep only the latest backups", func(t *testing.T) {
+		// Given
+		store := newBackedUpStore(t, 2)
+
+		// When
+		for range 4 {
+			store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		}
+
+		// Then
+		backups, _ := store.(models.Backuper).Backups()
+		asserts.Equal(2, len(backups))
+		latest, _ := store.(models.Backuper).BackupExpenses(backups[0].Id)
+		asserts.Equal(3, len(latest))
+	})
+
+	t.Run("✅ should restore a backup", func(t *testing.T) {
+		// Given
+		store := newBackedUpStore(t, 10)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		backup, _ := store.(models.Backuper).CreateBackup()
+		store.Delete(1)
+
+		// When
+		err := store.(models.Backuper).RestoreBackup(backup.Id)
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(1, len(store.Find(models.Query{})))
+		asserts.Equal(0, len(store.Trashed()))
+		verified, verifyErr := store.(models.Verifier).Verify()
+		asserts.Nil(verifyErr)
+		asserts.Equal(1, verified)
+		asserts.Nil(store.Add(models.Expense{Amount: 10, Description: "Coffee"}))
+	})
+
+	t.Run("❌ should not restore a backup that does not exist", func(t *testing.T) {
+		// Given
+		store := newBackedUpStore(t, 10)
+
+		// When
+		err := store.(models.Backuper).RestoreBackup("20240805-120000.000")
+		invalidErr := store.(models.Backuper).RestoreBackup("../test.csv")
+
+		// Then
+		asserts.EqualError(err, "backup 20240805-120000.000 not found")
+		asserts.EqualError(invalidErr, `invalid backup ID "../test.csv"`)
+	})
+}
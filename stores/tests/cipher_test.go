@@ -0,0 +1,229 @@
+package tests
+
+import (
+	"bytes"
+	"expense-tracker/models"
+	"expense-tracker/stores"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCipher(t *testing.T) {
+	asserts := assert.New(t)
+
+	key := bytes.Repeat([]byte{7}, 32)
+
+	t.Run("✅ should decrypt what was encrypted with a passphrase", func(t *testing.T) {
+		// Given
+		cipher, _ := stores.NewPassphraseCipher("secret")
+		encrypted, err := cipher.Encode([]byte("ID,Description\n"))
+
+		// When
+		reader, _ := stores.NewPassphraseCipher("secret")
+		plain, decodeErr := reader.Decode(encrypted)
+
+		// Then
+		asserts.Nil(err)
+		asserts.Nil(decodeErr)
+		asserts.True(reader.Encoded(encrypted))
+		asserts.NotContains(string(encrypted), "Description")
+		asserts.Equal("ID,Description\n", string(plain))
+	})
+
+	t.Run("❌ should not decrypt with the wrong passphrase", func(t *testing.T) {
+		// Given
+		cipher, _ := stores.NewPassphraseCipher("secret")
+		encrypted, _ := cipher.Encode([]byte("ID,Description\n"))
+
+		// When
+		reader, _ := stores.NewPassphraseCipher("guess")
+		_, err := reader.Decode(encrypted)
+
+		// Then
+		asserts.EqualError(err, "wrong passphrase or key, or altered content")
+		asserts.ErrorIs(err, stores.ErrWrongKey)
+	})
+
+	t.Run("❌ should not open an encrypted ledger without the key", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		cipher, _ := stores.NewKeyCipher(key)
+		stores.NewCsvStore(filename, stores.WithCodec(cipher)).Add(models.Expense{Amount: 20, Description: "Lunch"})
+		other, _ := stores.NewKeyCipher(bytes.Repeat([]byte{8}, 32))
+
+		// When
+		missing := recoverError(func() { stores.NewCsvStore(filename) })
+		wrong := recoverError(func() { stores.NewCsvStore(filename, stores.WithCodec(other)) })
+
+		// Then
+		asserts.ErrorIs(missing, stores.ErrKeyRequired)
+		asserts.ErrorIs(wrong, stores.ErrWrongKey)
+	})
+
+	t.Run("❌ should not decrypt altered content", func(t *testing.T) {
+		// Given
+		cipher, _ := stores.NewKeyCipher(key)
+		encrypted, _ := cipher.Encode([]byte("ID,Description\n"))
+		encrypted[len(encrypted)-1] ^= 1
+
+		// When
+		_, err := cipher.Decode(encrypted)
+
+		// Then
+		asserts.EqualError(err, "wrong passphrase or key, or altered content")
+	})
+
+	t.Run("❌ should not accept a key of the wrong size", func(t *testing.T) {
+		// When
+		_, err := stores.NewKeyCipher([]byte("short"))
+
+		// Then
+		asserts.EqualError(err, "key must be 32 bytes")
+	})
+
+	t.Run("✅ should read a hex encoded key file", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "ledger.key")
+		os.WriteFile(filename, []byte("0707070707070707070707070707070707070707070707070707070707070707\n"), 0600)
+
+		// When
+		read, err := stores.ReadKeyFile(filename)
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(key, read)
+	})
+
+	t.Run("✅ should keep the ledger encrypted at rest", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		cipher, _ := stores.NewKeyCipher(key)
+		store := stores.NewCsvStore(filename, stores.WithCodec(cipher))
+
+		// When
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+
+		// Then
+		content, _ := os.ReadFile(filename)
+		asserts.NotContains(string(content), "Lunch")
+		reloaded := stores.NewCsvStore(filename, stores.WithCodec(cipher))
+		asserts.Equal("Lunch", reloaded.Find(models.Query{})[0].Description)
+		asserts.Panics(func() { stores.NewCsvStore(filename) })
+	})
+
+	t.Run("✅ should encrypt and decrypt an existing ledger", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		stores.NewCsvStore(filename).Add(models.Expense{Amount: 20, Description: "Lunch"})
+		cipher, _ := stores.NewKeyCipher(key)
+		store := stores.NewCsvStore(filename, stores.WithCodec(cipher))
+
+		// When
+		encryptErr := store.(models.Encrypter).Encrypt()
+		encrypted, _ := os.ReadFile(filename)
+		decryptErr := store.(models.Encrypter).Decrypt()
+
+		// Then
+		asserts.Nil(encryptErr)
+		asserts.Nil(decryptErr)
+		asserts.NotContains(string(encrypted), "Lunch")
+		asserts.Equal("Lunch", stores.NewCsvStore(filename).Find(models.Query{})[0].Description)
+	})
+
+	t.Run("✅ should keep the rates, accounts and budgets encrypted at rest", func(t *testing.T) {
+		// Given
+		directory := t.TempDir()
+		cipher, _ := stores.NewKeyCipher(key)
+		august := time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+		// When
+		stores.NewCsvRateStore(filepath.Join(directory, "rates.csv"), stores.WithCodec(cipher)).Set(models.Rate{From: "EUR", To: "USD", Rate: 1.09, Date: august})
+		stores.NewCsvAccountStore(filepath.Join(directory, "accounts.csv"), stores.WithCodec(cipher)).Save(models.Account{Name: "checking"})
+		stores.NewCsvBudgetStore(filepath.Join(directory, "budgets.csv"), stores.WithCodec(cipher)).Set(models.Budget{Category: "food", Amount: 400, From: august})
+
+		// Then
+		for _, name := range []string{"rates.csv", "accounts.csv", "budgets.csv"} {
+			content, _ := os.ReadFile(filepath.Join(directory, name))
+			asserts.True(cipher.Encoded(content), name)
+		}
+		asserts.Equal(1, len(stores.NewCsvRateStore(filepath.Join(directory, "rates.csv"), stores.WithCodec(cipher)).Rates()))
+		asserts.Panics(func() { stores.NewCsvBudgetStore(filepath.Join(directory, "budgets.csv")) })
+	})
+
+	t.Run("✅ should encrypt and decrypt existing rates", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "rates.csv")
+		august := time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)
+		stores.NewCsvRateStore(filename).Set(models.Rate{From: "EUR", To: "USD", Rate: 1.09, Date: august})
+		cipher, _ := stores.NewKeyCipher(key)
+		store := stores.NewCsvRateStore(filename, stores.WithCodec(cipher))
+
+		// When
+		encryptErr := store.(models.Encrypter).Encrypt()
+		encrypted, _ := os.ReadFile(filename)
+		decryptErr := store.(models.Encrypter).Decrypt()
+
+		// Then
+		asserts.Nil(encryptErr)
+		asserts.Nil(decryptErr)
+		asserts.True(cipher.Encoded(encrypted))
+		asserts.Equal(1, len(stores.NewCsvRateStore(filename).Rates()))
+	})
+
+	t.Run("✅ should encrypt and decrypt the backups without backing up the plain ledger", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		backups := stores.WithBackups(stores.BackupPolicy{Count: 10})
+		plain := stores.NewCsvStore(filename, backups)
+		plain.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		plain.Add(models.Expense{Amount: 10, Description: "Coffee"})
+		cipher, _ := stores.NewKeyCipher(key)
+		store := stores.NewCsvStore(filename, backups, stores.WithCodec(cipher))
+
+		// When
+		encryptErr := store.(models.Encrypter).Encrypt()
+		encrypted, _ := store.(models.Backuper).Backups()
+		backupContent, _ := os.ReadFile(filepath.Join(filepath.Dir(filename), "backups", "test.csv."+encrypted[0].Id))
+		decryptErr := store.(models.Encrypter).Decrypt()
+
+		// Then
+		asserts.Nil(encryptErr)
+		asserts.Nil(decryptErr)
+		asserts.Equal(2, len(encrypted))
+		asserts.True(cipher.Encoded(backupContent))
+		decrypted, _ := store.(models.Backuper).Backups()
+		asserts.Equal(2, len(decrypted))
+		expenses, err := stores.NewCsvStore(filename, backups).(models.Backuper).BackupExpenses(decrypted[0].Id)
+		asserts.Nil(err)
+		asserts.Equal("Lunch", expenses[0].Description)
+	})
+
+	t.Run("✅ should keep the event log encrypted at rest", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "events.csv")
+		cipher, _ := stores.NewKeyCipher(key)
+		store := stores.NewEventStore(filename, stores.WithCodec(cipher))
+
+		// When
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+
+		// Then
+		content, _ := os.ReadFile(filename)
+		asserts.NotContains(string(content), "Coffee")
+		asserts.Equal(2, len(stores.NewEventStore(filename, stores.WithCodec(cipher)).Find(models.Query{})))
+	})
+}
+
+// recoverError returns the error the function panics with.
+func recoverError(open func()) (err error) {
+	defer func() {
+		err, _ = recover().(error)
+	}()
+	open()
+	return nil
+}
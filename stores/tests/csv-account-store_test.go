@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"expense-tracker/stores"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCsvAccountStore(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("✅ should persist the accounts", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "accounts.csv")
+		store := stores.NewCsvAccountStore(filename)
+
+		// When
+		err := store.Save(models.Account{Name: "checking", OpeningBalance: 1000})
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(models.Accounts{{Name: "checking", OpeningBalance: 1000}}, stores.NewCsvAccountStore(filename).Accounts())
+	})
+
+	t.Run("✅ should replace the account with the same name", func(t *testing.T) {
+		// Given
+		store := stores.NewCsvAccountStore(filepath.Join(t.TempDir(), "accounts.csv"))
+		store.Save(models.Account{Name: "checking", OpeningBalance: 1000})
+
+		// When
+		err := store.Save(models.Account{Name: "checking", OpeningBalance: 500})
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(models.Accounts{{Name: "checking", OpeningBalance: 500}}, store.Accounts())
+	})
+
+	t.Run("❌ should not save an account without a name", func(t *testing.T) {
+		// Given
+		store := stores.NewCsvAccountStore(filepath.Join(t.TempDir(), "accounts.csv"))
+
+		// When
+		err := store.Save(models.Account{OpeningBalance: 500})
+
+		// Then
+		asserts.Equal("account name is required", err.Error())
+	})
+}
@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"expense-tracker/stores"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCsvAuditStore(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("✅ should record a revision of every change", func(t *testing.T) {
+		// Given
+		audit := stores.NewCsvAuditStore(filepath.Join(t.TempDir(), "audit.csv"))
+		store := stores.NewAuditedStore(stores.NewInMemoryStore(), audit, "alice")
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+
+		// When
+		store.Update(models.Expense{Id: 1, Amount: 25, Description: "Lunch"})
+		store.Delete(1)
+		store.Restore(1)
+
+		// Then
+		revisions := audit.Revisions(1)
+		asserts.Equal(4, len(revisions))
+		asserts.Equal(models.ActionAdd, revisions[0].Action)
+		asserts.Equal(models.ActionUpdate, revisions[1].Action)
+		asserts.Equal(25, revisions[1].Expense.Amount)
+		asserts.Equal(models.ActionDelete, revisions[2].Action)
+		asserts.NotNil(revisions[2].Expense.DeletedAt)
+		asserts.Equal(models.ActionRestore, revisions[3].Action)
+		asserts.Equal(4, revisions[3].Version)
+		asserts.Equal("alice", revisions[3].Author)
+	})
+
+	t.Run("✅ should record the purge of trashed expenses", func(t *testing.T) {
+		// Given
+		audit := stores.NewCsvAuditStore(filepath.Join(t.TempDir(), "audit.csv"))
+		store := stores.NewAuditedStore(stores.NewInMemoryStore(), audit, "alice")
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Delete(1)
+
+		// When
+		store.EmptyTrash(time.Now().Add(time.Hour))
+
+		// Then
+		revisions := audit.Revisions(1)
+		asserts.Equal(3, len(revisions))
+		asserts.Equal(models.ActionPurge, revisions[2].Action)
+	})
+
+	t.Run("✅ should persist the revisions", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "audit.csv")
+		store := stores.NewAuditedStore(stores.NewInMemoryStore(), stores.NewCsvAuditStore(filename), "alice")
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Update(models.Expense{Id: 1, Amount: 25, Description: "Dinner"})
+
+		// When
+		revisions := stores.NewCsvAuditStore(filename).Revisions(1)
+
+		// Then
+		asserts.Equal(2, len(revisions))
+		asserts.Equal(2, revisions[1].Version)
+		asserts.Equal("Dinner", revisions[1].Expense.Description)
+		asserts.Equal(25, revisions[1].Expense.Amount)
+		asserts.Equal("alice", revisions[1].Author)
+		asserts.Equal(models.ActionUpdate, revisions[1].Action)
+	})
+}
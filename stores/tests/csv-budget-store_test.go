@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"expense-tracker/stores"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCsvBudgetStore(t *testing.T) {
+	asserts := assert.New(t)
+
+	august := models.MonthStart(2024, time.August)
+
+	t.Run("✅ should persist the budgets", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "budgets.csv")
+		store := stores.NewCsvBudgetStore(filename)
+
+		// When
+		err := store.Set(models.Budget{Category: "food", Amount: 400, Rollover: true, From: august})
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(models.Budgets{{Category: "food", Amount: 400, Rollover: true, From: august}}, stores.NewCsvBudgetStore(filename).Budgets())
+	})
+
+	t.Run("✅ should replace the budget of the same category and month", func(t *testing.T) {
+		// Given
+		store := stores.NewCsvBudgetStore(filepath.Join(t.TempDir(), "budgets.csv"))
+		store.Set(models.Budget{Category: "food", Amount: 400, From: august})
+
+		// When
+		err := store.Set(models.Budget{Category: "Food", Amount: 300, From: august})
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(1, len(store.Budgets()))
+		asserts.Equal(300, store.Budgets()[0].Amount)
+	})
+
+	t.Run("❌ should not set a budget with a negative amount", func(t *testing.T) {
+		// Given
+		store := stores.NewCsvBudgetStore(filepath.Join(t.TempDir(), "budgets.csv"))
+
+		// When
+		err := store.Set(models.Budget{Category: "food", Amount: -1, From: august})
+
+		// Then
+		asserts.Equal("budget amount cannot be negative", err.Error())
+	})
+}
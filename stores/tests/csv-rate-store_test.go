@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"expense-tracker/stores"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCsvRateStore(t *testing.T) {
+	asserts := assert.New(t)
+
+	august := time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("✅ should persist the rates", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "rates.csv")
+		store := stores.NewCsvRateStore(filename)
+
+		// When
+		err := store.Set(models.Rate{From: "EUR", To: "USD", Rate: 1.09, Date: august})
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(models.Rates{{From: "EUR", To: "USD", Rate: 1.09, Date: august}}, stores.NewCsvRateStore(filename).Rates())
+	})
+
+	t.Run("✅ should replace the rate of the same pair and date", func(t *testing.T) {
+		// Given
+		store := stores.NewCsvRateStore(filepath.Join(t.TempDir(), "rates.csv"))
+		store.Set(models.Rate{From: "EUR", To: "USD", Rate: 1.09, Date: august})
+
+		// When
+		err := store.Set(models.Rate{From: "EUR", To: "USD", Rate: 1.1, Date: august})
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(1, len(store.Rates()))
+		asserts.Equal(1.1, store.Rates()[0].Rate)
+	})
+
+	t.Run("❌ should not set a non positive rate", func(t *testing.T) {
+		// Given
+		store := stores.NewCsvRateStore(filepath.Join(t.TempDir(), "rates.csv"))
+
+		// When
+		err := store.Set(models.Rate{From: "EUR", To: "USD", Rate: 0, Date: august})
+
+		// Then
+		asserts.Equal("rate must be positive", err.Error())
+		asserts.Equal(0, len(store.Rates()))
+	})
+}
@@ -5,6 +5,9 @@ import (
 	"expense-tracker/models/tests/dsl"
 	"expense-tracker/stores"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,15 +19,15 @@ func TestCsvStore(t *testing.T) {
 
 	t.Run("✅ should instantiate an csv store", func(t *testing.T) {
 		// When
-		store := stores.NewCsvStore("test.csv")
+		store := newCsvStore(t)
 		// Then
 		asserts.NotNil(store)
 	})
 
 	t.Run("✅ should add an expense with a description and amount", func(t *testing.T) {
 		// Given
-		store := stores.NewCsvStore()
-		amount := 20
+		store := newCsvStore(t)
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
@@ -37,7 +40,7 @@ func TestCsvStore(t *testing.T) {
 		asserts.Nil(err)
 		asserts.Equal(1, len(expenses))
 		asserts.Equal(1, expenses[0].Id)
-		asserts.Equal(20, expenses[0].Amount)
+		asserts.Equal(amount, expenses[0].Amount)
 		asserts.Equal("Lunch", expenses[0].Description)
 		asserts.NotNil(expenses[0].CreatedAt)
 		asserts.Nil(expenses[0].UpdatedAt)
@@ -46,8 +49,8 @@ func TestCsvStore(t *testing.T) {
 
 	t.Run("✅ should add two expenses", func(t *testing.T) {
 		// Given
-		store := stores.NewCsvStore()
-		amount := 20
+		store := newCsvStore(t)
+		amount := 2000
 		description := "Lunch"
 
 		expense1 := models.Expense{Amount: amount, Description: description}
@@ -69,7 +72,7 @@ func TestCsvStore(t *testing.T) {
 
 	t.Run("❌ should not add an expense with a negative amount", func(t *testing.T) {
 		// Given
-		store := stores.NewCsvStore()
+		store := newCsvStore(t)
 		amount := -20
 		description := "Lunch"
 
@@ -86,8 +89,8 @@ func TestCsvStore(t *testing.T) {
 
 	t.Run("✅ should update an expense", func(t *testing.T) {
 		// Given
-		store := stores.NewCsvStore()
-		amount := 20
+		store := newCsvStore(t)
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
@@ -109,8 +112,8 @@ func TestCsvStore(t *testing.T) {
 
 	t.Run("❌ should not updated an non-existent expense", func(t *testing.T) {
 		// Given
-		store := stores.NewCsvStore()
-		amount := 20
+		store := newCsvStore(t)
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
@@ -132,8 +135,8 @@ func TestCsvStore(t *testing.T) {
 
 	t.Run("❌ should not update an expense with a negative amount", func(t *testing.T) {
 		// Given
-		store := stores.NewCsvStore()
-		amount := 20
+		store := newCsvStore(t)
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
@@ -154,8 +157,8 @@ func TestCsvStore(t *testing.T) {
 
 	t.Run("✅ should delete an expense", func(t *testing.T) {
 		// Given
-		store := stores.NewCsvStore()
-		amount := 20
+		store := newCsvStore(t)
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
@@ -171,8 +174,8 @@ func TestCsvStore(t *testing.T) {
 
 	t.Run("❌ should not delete an non-existent expense", func(t *testing.T) {
 		// Given
-		store := stores.NewCsvStore()
-		amount := 20
+		store := newCsvStore(t)
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
@@ -191,8 +194,8 @@ func TestCsvStore(t *testing.T) {
 
 	t.Run("✅ should list all expenses", func(t *testing.T) {
 		// Given
-		store := stores.NewCsvStore()
-		amount := 20
+		store := newCsvStore(t)
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
@@ -215,7 +218,7 @@ func TestCsvStore(t *testing.T) {
 
 	t.Run("✅ should print the header when there are no expenses", func(t *testing.T) {
 		// Given
-		store := stores.NewCsvStore()
+		store := newCsvStore(t)
 
 		// When
 		result := dsl.OutputToString(store.List)
@@ -226,14 +229,14 @@ func TestCsvStore(t *testing.T) {
 
 	t.Run("✅ should print the summary of all expenses", func(t *testing.T) {
 		// Given
-		store := stores.NewCsvStore()
-		amount := 20
+		store := newCsvStore(t)
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
 
-		amount = 15
+		amount = 1500
 		description = "Dinner"
 		expense = models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
@@ -247,19 +250,19 @@ func TestCsvStore(t *testing.T) {
 
 	t.Run("✅ should print the summary of all expenses for a specific month of the current year", func(t *testing.T) {
 		// Given
-		store := stores.NewCsvStore()
-		amount := 20
+		store := newCsvStore(t)
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
 
-		amount = 15
+		amount = 1500
 		description = "Dinner"
 		expense = models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
 
-		amount = 50
+		amount = 5000
 		description = "Dinner"
 		expense = models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
@@ -280,19 +283,19 @@ func TestCsvStore(t *testing.T) {
 
 	t.Run("✅ should print 0 when there are no expenses for a specific month of the current year", func(t *testing.T) {
 		// Given
-		store := stores.NewCsvStore()
-		amount := 20
+		store := newCsvStore(t)
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
 
-		amount = 15
+		amount = 1500
 		description = "Dinner"
 		expense = models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
 
-		amount = 50
+		amount = 5000
 		description = "Dinner"
 		expense = models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
@@ -313,24 +316,24 @@ func TestCsvStore(t *testing.T) {
 
 	t.Run("✅ should print the expenses from a specific month and current year taking on account the updated at", func(t *testing.T) {
 		// Given
-		store := stores.NewCsvStore()
-		amount := 20
+		store := newCsvStore(t)
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
 
-		amount = 15
+		amount = 1500
 		description = "Dinner"
 		expense = models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
 
-		amount = 50
+		amount = 5000
 		description = "Dinner"
 		expense = models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
 
-		amount = 100
+		amount = 10000
 		description = "Lunch"
 		expense = models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
@@ -349,6 +352,7 @@ func TestCsvStore(t *testing.T) {
 
 		updateAt3 := time.Date(time.Now().Year()+1, time.January, 1, 0, 0, 0, 0, time.UTC)
 		expenses[3].UpdatedAt = &updateAt3
+		store.Reindex()
 
 		// When
 		result := dsl.OutputToString(func() {
@@ -358,4 +362,416 @@ func TestCsvStore(t *testing.T) {
 		// Then
 		asserts.Equal("Total expenses: 20\n", result)
 	})
+
+	t.Run("✅ should list expenses sorted by amount in descending order", func(t *testing.T) {
+		// Given
+		store := newCsvStore(t)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 50, Description: "Dinner"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+
+		// When
+		expenses := store.Find(models.Query{SortBy: models.SortByAmount, Desc: true})
+
+		// Then
+		asserts.Equal(3, len(expenses))
+		asserts.Equal(2, expenses[0].Id)
+		asserts.Equal(1, expenses[1].Id)
+		asserts.Equal(3, expenses[2].Id)
+		asserts.Equal(1, (*store.Expenses)[0].Id)
+	})
+
+	t.Run("✅ should paginate expenses with limit and offset", func(t *testing.T) {
+		// Given
+		store := newCsvStore(t)
+		for i := 1; i <= 5; i++ {
+			store.Add(models.Expense{Amount: i, Description: "Lunch"})
+		}
+
+		// When
+		expenses := store.Find(models.Query{Limit: 2, Offset: 2})
+
+		// Then
+		asserts.Equal(2, len(expenses))
+		asserts.Equal(3, expenses[0].Id)
+		asserts.Equal(4, expenses[1].Id)
+		asserts.Equal(0, len(store.Find(models.Query{Offset: 10})))
+	})
+
+	t.Run("✅ should list only the last expenses with tail", func(t *testing.T) {
+		// Given
+		store := newCsvStore(t)
+		for i := 1; i <= 5; i++ {
+			store.Add(models.Expense{Amount: i, Description: "Lunch"})
+		}
+		expenses := *store.Expenses
+		expectedMessage := models.HeaderFormat + "\n" + dsl.JoinMessage(expenses[3:4]) + dsl.JoinMessage(expenses[4:5])
+
+		// When
+		result := dsl.OutputToString(func() {
+			store.ListBy(models.Query{Tail: 2})
+		})
+
+		// Then
+		asserts.Equal(expectedMessage, result)
+	})
+
+	t.Run("✅ should print the summary grouped by month with the delta against the previous month", func(t *testing.T) {
+		// Given
+		store := newCsvStore(t)
+		store.Add(models.Expense{Amount: 2000, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 1000, Description: "Dinner"})
+		store.Add(models.Expense{Amount: 1500, Description: "Dinner"})
+
+		expenses := *store.Expenses
+		expenses[0].CreatedAt = time.Date(2024, time.July, 3, 0, 0, 0, 0, time.UTC)
+		expenses[1].CreatedAt = time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)
+		expenses[2].CreatedAt = time.Date(2024, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+		expectedMessage := models.SummaryHeaderFormat + "\n" +
+			fmt.Sprintf(models.SummaryStringFormat, "2024-07", 1, "20", "20.00", "", "0", "-20") +
+			fmt.Sprintf(models.SummaryStringFormat, "2024-08", 2, "25", "12.50", "+5", "0", "-25")
+
+		// When
+		result := dsl.OutputToString(func() {
+			store.SummaryGroupedBy(models.GroupByMonth)
+		})
+
+		// Then
+		asserts.Equal(expectedMessage, result)
+	})
+
+	t.Run("✅ should persist the category of an expense", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		store := stores.NewCsvStore(filename)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch", Category: "food"})
+
+		// When
+		reloaded := stores.NewCsvStore(filename).(*stores.CsvStore)
+
+		// Then
+		expenses := *reloaded.Expenses
+		asserts.Equal(1, len(expenses))
+		asserts.Equal("food", expenses[0].Category)
+	})
+
+	t.Run("✅ should print the income, expenses and net balance when there is income", func(t *testing.T) {
+		// Given
+		store := newCsvStore(t)
+		store.Add(models.Expense{Amount: 2000, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 300000, Description: "Salary", Kind: models.KindIncome})
+		store.Add(models.Expense{Amount: 50000, Description: "Savings", Kind: models.KindTransfer})
+
+		// When
+		result := dsl.OutputToString(store.Summary)
+
+		// Then
+		asserts.Equal("Total income: 3000\nTotal expenses: 20\nNet balance: 2980\n", result)
+	})
+
+	t.Run("❌ should not add an income with a negative amount", func(t *testing.T) {
+		// Given
+		store := newCsvStore(t)
+
+		// When
+		err := store.Add(models.Expense{Amount: -3000, Description: "Salary", Kind: models.KindIncome})
+
+		// Then
+		asserts.Equal("income amount cannot be negative", err.Error())
+		asserts.Equal(0, len(*store.Expenses))
+	})
+
+	t.Run("✅ should account a refund in the month it was refunded", func(t *testing.T) {
+		// Given
+		store := newCsvStore(t)
+		store.Add(models.Expense{Amount: 4000, Description: "Order"})
+		err := store.Add(models.Expense{Amount: 1500, Description: "Refund", Kind: models.KindRefund, RefundOf: 1})
+
+		expenses := *store.Expenses
+		expenses[0].CreatedAt = time.Date(time.Now().Year(), time.January, 10, 0, 0, 0, 0, time.UTC)
+		expenses[1].CreatedAt = time.Date(time.Now().Year(), time.February, 3, 0, 0, 0, 0, time.UTC)
+
+		// When
+		january := dsl.OutputToString(func() {
+			store.SummaryForMonth(time.January)
+		})
+		february := dsl.OutputToString(func() {
+			store.SummaryForMonth(time.February)
+		})
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal("Total expenses: 40\n", january)
+		asserts.Equal("Total expenses: -15\n", february)
+		asserts.Equal(2500, expenses[0].Net(expenses.Refunded()))
+	})
+
+	t.Run("✅ should persist the split of an expense", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		store := stores.NewCsvStore(filename)
+		shares := models.Shares{{Person: "alice", Amount: 10}, {Person: "me", Amount: 10}}
+		store.Add(models.Expense{Amount: 20, Description: "Lunch", PaidBy: "me", Split: shares})
+
+		// When
+		reloaded := stores.NewCsvStore(filename).(*stores.CsvStore)
+
+		// Then
+		expenses := *reloaded.Expenses
+		asserts.Equal(1, len(expenses))
+		asserts.Equal("me", expenses[0].PaidBy)
+		asserts.Equal(shares, expenses[0].Split)
+	})
+
+	t.Run("✅ should move a deleted expense to the trash", func(t *testing.T) {
+		// Given
+		store := newCsvStore(t)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+
+		// When
+		err := store.Delete(1)
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(1, len(store.Find(models.Query{})))
+		trashed := store.Trashed()
+		asserts.Equal(1, len(trashed))
+		asserts.Equal("Lunch", trashed[0].Description)
+		asserts.NotNil(trashed[0].DeletedAt)
+	})
+
+	t.Run("✅ should not reuse the ID of a trashed expense", func(t *testing.T) {
+		// Given
+		store := newCsvStore(t)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Delete(1)
+
+		// When
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+
+		// Then
+		asserts.Equal(2, store.Find(models.Query{})[0].Id)
+		asserts.Equal(2, store.LastId())
+	})
+
+	t.Run("✅ should restore a trashed expense", func(t *testing.T) {
+		// Given
+		store := newCsvStore(t)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Delete(1)
+
+		// When
+		err := store.Restore(1)
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(0, len(store.Trashed()))
+		expense, err := store.Get(1)
+		asserts.Nil(err)
+		asserts.Nil(expense.DeletedAt)
+	})
+
+	t.Run("❌ should not restore an expense that is not in the trash", func(t *testing.T) {
+		// Given
+		store := newCsvStore(t)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+
+		// When
+		err := store.Restore(1)
+
+		// Then
+		asserts.EqualError(err, "expense with ID 1 not found in trash")
+	})
+
+	t.Run("✅ should empty only the expenses trashed before a date", func(t *testing.T) {
+		// Given
+		store := newCsvStore(t)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+		store.Delete(1)
+		store.Delete(2)
+		longAgo := time.Now().AddDate(0, 0, -40)
+		store.Trashed()[0].DeletedAt = &longAgo
+
+		// When
+		emptied, err := store.EmptyTrash(time.Now().AddDate(0, 0, -30))
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(1, emptied)
+		trashed := store.Trashed()
+		asserts.Equal(1, len(trashed))
+		asserts.Equal("Coffee", trashed[0].Description)
+	})
+
+	t.Run("✅ should persist the trash", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		store := stores.NewCsvStore(filename)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+		store.Delete(1)
+
+		// When
+		reloaded := stores.NewCsvStore(filename).(*stores.CsvStore)
+
+		// Then
+		asserts.Equal(1, len(*reloaded.Expenses))
+		asserts.Equal("Coffee", (*reloaded.Expenses)[0].Description)
+		asserts.Equal(1, len(*reloaded.Trash))
+		asserts.Equal("Lunch", (*reloaded.Trash)[0].Description)
+		asserts.WithinDuration(time.Now(), *(*reloaded.Trash)[0].DeletedAt, 2*time.Second)
+	})
+
+	t.Run("✅ should read the trash of files that kept the deletion date only", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		header := "ID,Description,Amount,Created At,Updated At,Category,Currency,Kind,Refund Of,Account,To Account,Paid By,Split,Recurring,Deleted At\n"
+		os.WriteFile(filename, []byte(header+"1,Lunch,20,2024-08-01,,,,expense,,,,,,,2024-08-03\n"), 0644)
+
+		// When
+		store := stores.NewCsvStore(filename).(*stores.CsvStore)
+
+		// Then
+		asserts.Equal(1, len(*store.Trash))
+		asserts.Equal(time.Date(2024, time.August, 3, 0, 0, 0, 0, time.UTC), *(*store.Trash)[0].DeletedAt)
+	})
+
+	t.Run("✅ should verify the chain of hashes of the ledger", func(t *testing.T) {
+		// Given
+		store := newCsvStore(t)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+
+		// When
+		verified, err := store.Verify()
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(2, verified)
+		asserts.Len(store.Head(), 64)
+	})
+
+	t.Run("❌ should not accept a sealed ledger whose last rows were removed", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		store := stores.NewCsvStore(filename)
+		store.Add(models.Expense{Amount: 2000, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 1000, Description: "Coffee"})
+		content, _ := os.ReadFile(filename)
+		lines := strings.SplitAfter(string(content), "\n")
+		os.WriteFile(filename, []byte(strings.Join(lines[:2], "")), 0644)
+
+		// When
+		truncated := stores.NewCsvStore(filename).(*stores.CsvStore)
+		verified, err := truncated.Verify()
+		addErr := truncated.Add(models.Expense{Amount: 500, Description: "Bus"})
+
+		// Then
+		asserts.Equal(1, verified)
+		asserts.ErrorContains(err, "the chain ends after 1 rows instead of 2")
+		asserts.ErrorContains(addErr, "was altered, refusing to save over it")
+	})
+
+	t.Run("❌ should not accept a sealed ledger whose hashes were stripped", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		store := stores.NewCsvStore(filename)
+		store.Add(models.Expense{Amount: 2000, Description: "Lunch"})
+		content, _ := os.ReadFile(filename)
+		stripped := ""
+		for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+			stripped += line[:strings.LastIndex(line, ",")] + "\n"
+		}
+		os.WriteFile(filename, []byte(stripped), 0644)
+
+		// When
+		reloaded := stores.NewCsvStore(filename).(*stores.CsvStore)
+		_, err := reloaded.Verify()
+		addErr := reloaded.Add(models.Expense{Amount: 500, Description: "Bus"})
+
+		// Then
+		asserts.EqualError(err, "broken link at line 2: hash is missing")
+		asserts.ErrorContains(addErr, "was altered, refusing to save over it")
+	})
+
+	t.Run("❌ should report the first broken link of an altered ledger", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		store := stores.NewCsvStore(filename)
+		store.Add(models.Expense{Amount: 2000, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 1000, Description: "Coffee"})
+		content, _ := os.ReadFile(filename)
+		os.WriteFile(filename, []byte(strings.Replace(string(content), "Coffee,10", "Coffee,1", 1)), 0644)
+
+		// When
+		altered := stores.NewCsvStore(filename).(*stores.CsvStore)
+		verified, err := altered.Verify()
+		addErr := altered.Add(models.Expense{Amount: 5, Description: "Bus"})
+
+		// Then
+		asserts.Equal(1, verified)
+		asserts.EqualError(err, "broken link at line 3 (expense ID 2): hash does not match")
+		asserts.ErrorContains(addErr, "was altered, refusing to save over it")
+	})
+
+	t.Run("✅ should seal a ledger written without hashes on the next change", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		os.WriteFile(filename, []byte("ID,Description,Amount,Created At,Updated At\n1,Lunch,20,2024-08-05,\n"), 0644)
+		store := stores.NewCsvStore(filename).(*stores.CsvStore)
+
+		// When
+		err := store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+
+		// Then
+		asserts.Nil(err)
+		verified, verifyErr := store.Verify()
+		asserts.Nil(verifyErr)
+		asserts.Equal(2, verified)
+	})
+
+	t.Run("✅ should never give the ID of a purged expense again", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		store := stores.NewCsvStore(filename)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+		store.Delete(2)
+		store.EmptyTrash(time.Now().Add(time.Hour))
+
+		// When
+		reloaded := stores.NewCsvStore(filename)
+		err := reloaded.Add(models.Expense{Amount: 5, Description: "Bus"})
+
+		// Then
+		asserts.Nil(err)
+		_, err = reloaded.Get(2)
+		asserts.NotNil(err)
+		expense, err := reloaded.Get(3)
+		asserts.Nil(err)
+		asserts.Equal("Bus", expense.Description)
+	})
+
+	t.Run("✅ should continue the IDs of a ledger written without a sequence", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		os.WriteFile(filename, []byte("ID,Description,Amount,Created At,Updated At\n7,Lunch,20,2024-08-05,\n"), 0644)
+		store := stores.NewCsvStore(filename)
+
+		// When
+		err := store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+
+		// Then
+		asserts.Nil(err)
+		expense, err := store.Get(8)
+		asserts.Nil(err)
+		asserts.Equal("Coffee", expense.Description)
+	})
+}
+
+func newCsvStore(t *testing.T) *stores.CsvStore {
+	return stores.NewCsvStore(filepath.Join(t.TempDir(), "test.csv")).(*stores.CsvStore)
 }
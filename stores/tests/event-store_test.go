@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"expense-tracker/stores"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventStore(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("✅ should rebuild the expenses from the events on load", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "events.csv")
+		store := stores.NewEventStore(filename)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+		store.Add(models.Expense{Amount: 30, Description: "Taxi"})
+		store.Update(models.Expense{Id: 1, Amount: 25, Description: "Dinner"})
+		store.Delete(2)
+		store.Delete(3)
+		store.Restore(3)
+
+		// When
+		reloaded := stores.NewEventStore(filename)
+
+		// Then
+		expenses := reloaded.Find(models.Query{})
+		asserts.Equal(2, len(expenses))
+		asserts.Equal("Dinner", expenses[0].Description)
+		asserts.Equal(25, expenses[0].Amount)
+		asserts.Equal("Taxi", expenses[1].Description)
+		asserts.Nil(expenses[1].DeletedAt)
+		trashed := reloaded.Trashed()
+		asserts.Equal(1, len(trashed))
+		asserts.Equal("Coffee", trashed[0].Description)
+	})
+
+	t.Run("✅ should only append to the log", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "events.csv")
+		store := stores.NewEventStore(filename)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+
+		// When
+		store.Delete(1)
+		store.EmptyTrash(time.Now().Add(time.Hour))
+
+		// Then
+		content, err := os.ReadFile(filename)
+		asserts.Nil(err)
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		asserts.Equal(4, len(lines))
+		asserts.True(strings.HasPrefix(lines[1], "1,added,"))
+		asserts.True(strings.HasPrefix(lines[2], "2,deleted,"))
+		asserts.True(strings.HasPrefix(lines[3], "3,purged,"))
+		asserts.Equal(0, len(stores.NewEventStore(filename).Trashed()))
+	})
+
+	t.Run("❌ should not append an event for an invalid change", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "events.csv")
+		store := stores.NewEventStore(filename)
+
+		// When
+		err := store.Add(models.Expense{Amount: -20, Description: "Lunch"})
+
+		// Then
+		asserts.EqualError(err, "amount cannot be negative")
+		_, statErr := os.Stat(filename)
+		asserts.True(os.IsNotExist(statErr))
+	})
+
+	t.Run("✅ should keep the expenses when compacting", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "events.csv")
+		store := stores.NewEventStore(filename)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+		store.Delete(2)
+
+		// When
+		err := store.(models.Compactor).Compact()
+		store.Add(models.Expense{Amount: 30, Description: "Taxi"})
+
+		// Then
+		asserts.Nil(err)
+		content, _ := os.ReadFile(filename)
+		asserts.Equal(2, len(strings.Split(strings.TrimSpace(string(content)), "\n")))
+		reloaded := stores.NewEventStore(filename)
+		expenses := reloaded.Find(models.Query{})
+		asserts.Equal(2, len(expenses))
+		asserts.Equal("Lunch", expenses[0].Description)
+		asserts.Equal(3, expenses[1].Id)
+		asserts.Equal(1, len(reloaded.Trashed()))
+	})
+
+	t.Run("✅ should take a snapshot every interval of events", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "events.csv")
+		store := stores.NewEventStore(filename)
+
+		// When
+		for range stores.SnapshotInterval {
+			store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		}
+		store.Delete(1)
+
+		// Then
+		_, err := os.Stat(filename + ".snapshot")
+		asserts.Nil(err)
+		reloaded := stores.NewEventStore(filename)
+		asserts.Equal(stores.SnapshotInterval-1, len(reloaded.Find(models.Query{})))
+		asserts.Equal(1, len(reloaded.Trashed()))
+	})
+
+	t.Run("✅ should never give the ID of a purged expense again", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "events.csv")
+		store := stores.NewEventStore(filename)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+		store.Delete(2)
+		store.EmptyTrash(time.Now().Add(time.Hour))
+		store.(models.Compactor).Compact()
+
+		// When
+		reloaded := stores.NewEventStore(filename)
+		err := reloaded.Add(models.Expense{Amount: 5, Description: "Bus"})
+
+		// Then
+		asserts.Nil(err)
+		expenses := reloaded.Find(models.Query{})
+		asserts.Equal(2, len(expenses))
+		asserts.Equal(3, expenses[1].Id)
+	})
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"expense-tracker/models"
+	"expense-tracker/stores"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGzip(t *testing.T) {
+	asserts := assert.New(t)
+
+	// gunzip reads a compressed file back
+	gunzip := func(filename string) string {
+		content, err := os.ReadFile(filename)
+		asserts.Nil(err)
+		reader, err := gzip.NewReader(bytes.NewReader(content))
+		asserts.Nil(err)
+		plain, err := io.ReadAll(reader)
+		asserts.Nil(err)
+		return string(plain)
+	}
+
+	t.Run("✅ should compress a ledger ending with .gz", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "archive.csv.gz")
+		store := stores.NewCsvStore(filename)
+
+		// When
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+
+		// Then
+		asserts.True(strings.HasPrefix(gunzip(filename), "ID,Description,Amount"))
+		asserts.Equal(2, len(stores.NewCsvStore(filename).Find(models.Query{})))
+		asserts.Equal(2, len(stores.NewStreamingCsvStore(filename).Find(models.Query{})))
+	})
+
+	t.Run("✅ should compress an encrypted ledger before encrypting it", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "archive.csv.gz")
+		cipher, err := stores.NewKeyCipher([]byte(strings.Repeat("k", 32)))
+		asserts.Nil(err)
+		stores.NewCsvStore(filename, stores.WithCodec(cipher)).Add(models.Expense{Amount: 20, Description: "Lunch"})
+
+		// When
+		expenses := stores.NewStreamingCsvStore(filename, stores.WithCodec(cipher)).Find(models.Query{})
+
+		// Then
+		asserts.Equal(1, len(expenses))
+		asserts.Equal("Lunch", expenses[0].Description)
+	})
+
+	t.Run("✅ should append to a compressed event log", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "events.csv.gz")
+		store := stores.NewEventStore(filename)
+
+		// When
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+
+		// Then
+		asserts.Equal(3, len(strings.Split(strings.TrimSpace(gunzip(filename)), "\n")))
+		asserts.Equal(2, len(stores.NewEventStore(filename).Find(models.Query{})))
+	})
+
+	t.Run("✅ should read the backups of a compressed ledger", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "archive.csv.gz")
+		store := stores.NewCsvStore(filename, stores.WithBackups(stores.BackupPolicy{Count: 10})).(*stores.CsvStore)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		backup, err := store.CreateBackup()
+		asserts.Nil(err)
+		store.Delete(1)
+
+		// When
+		backupExpenses, err := store.BackupExpenses(backup.Id)
+		restoreErr := store.RestoreBackup(backup.Id)
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(1, len(backupExpenses))
+		asserts.Nil(restoreErr)
+		asserts.Equal(1, len(stores.NewCsvStore(filename).Find(models.Query{})))
+	})
+
+	t.Run("✅ should export as compressed CSV or JSON by extension", func(t *testing.T) {
+		// Given
+		directory := t.TempDir()
+		expenses := models.Expenses{{Id: 1, Amount: 2000, Description: "Lunch"}, {Id: 2, Amount: 1000, Description: "Coffee"}}
+		exporter := stores.NewFileExporter()
+
+		// When
+		csvErr := exporter.Export(filepath.Join(directory, "export.csv.gz"), expenses)
+		jsonErr := exporter.Export(filepath.Join(directory, "export.json.gz"), expenses)
+
+		// Then
+		asserts.Nil(csvErr)
+		asserts.Nil(jsonErr)
+		lines := strings.Split(strings.TrimSpace(gunzip(filepath.Join(directory, "export.csv.gz"))), "\n")
+		asserts.Equal(3, len(lines))
+		asserts.True(strings.HasPrefix(lines[1], "1,Lunch,20,"))
+		exported := models.Expenses{}
+		asserts.Nil(json.Unmarshal([]byte(gunzip(filepath.Join(directory, "export.json.gz"))), &exported))
+		asserts.Equal(2, len(exported))
+		asserts.Equal("Coffee", exported[1].Description)
+	})
+}
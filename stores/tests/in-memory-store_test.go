@@ -24,7 +24,7 @@ func TestInMemoryStore(t *testing.T) {
 	t.Run("✅ should add an expense with a description and amount", func(t *testing.T) {
 		// Given
 		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
-		amount := 20
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
@@ -37,7 +37,7 @@ func TestInMemoryStore(t *testing.T) {
 		asserts.Nil(err)
 		asserts.Equal(1, len(expenses))
 		asserts.Equal(1, expenses[0].Id)
-		asserts.Equal(20, expenses[0].Amount)
+		asserts.Equal(amount, expenses[0].Amount)
 		asserts.Equal("Lunch", expenses[0].Description)
 		asserts.NotNil(expenses[0].CreatedAt)
 		asserts.Nil(expenses[0].UpdatedAt)
@@ -47,7 +47,7 @@ func TestInMemoryStore(t *testing.T) {
 	t.Run("✅ should add two expenses", func(t *testing.T) {
 		// Given
 		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
-		amount := 20
+		amount := 2000
 		description := "Lunch"
 
 		expense1 := models.Expense{Amount: amount, Description: description}
@@ -87,7 +87,7 @@ func TestInMemoryStore(t *testing.T) {
 	t.Run("✅ should update an expense", func(t *testing.T) {
 		// Given
 		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
-		amount := 20
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
@@ -110,7 +110,7 @@ func TestInMemoryStore(t *testing.T) {
 	t.Run("❌ should not updated an non-existent expense", func(t *testing.T) {
 		// Given
 		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
-		amount := 20
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
@@ -133,7 +133,7 @@ func TestInMemoryStore(t *testing.T) {
 	t.Run("❌ should not update an expense with a negative amount", func(t *testing.T) {
 		// Given
 		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
-		amount := 20
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
@@ -155,7 +155,7 @@ func TestInMemoryStore(t *testing.T) {
 	t.Run("✅ should delete an expense", func(t *testing.T) {
 		// Given
 		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
-		amount := 20
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
@@ -172,7 +172,7 @@ func TestInMemoryStore(t *testing.T) {
 	t.Run("❌ should not delete an non-existent expense", func(t *testing.T) {
 		// Given
 		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
-		amount := 20
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
@@ -193,7 +193,7 @@ func TestInMemoryStore(t *testing.T) {
 	t.Run("✅ should list all expenses", func(t *testing.T) {
 		// Given
 		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
-		amount := 20
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
@@ -228,13 +228,13 @@ func TestInMemoryStore(t *testing.T) {
 	t.Run("✅ should print the summary of all expenses", func(t *testing.T) {
 		// Given
 		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
-		amount := 20
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
 
-		amount = 15
+		amount = 1500
 		description = "Dinner"
 		expense = models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
@@ -249,18 +249,18 @@ func TestInMemoryStore(t *testing.T) {
 	t.Run("✅ should print the summary of all expenses for a specific month of the current year", func(t *testing.T) {
 		// Given
 		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
-		amount := 20
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
 
-		amount = 15
+		amount = 1500
 		description = "Dinner"
 		expense = models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
 
-		amount = 50
+		amount = 5000
 		description = "Dinner"
 		expense = models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
@@ -282,18 +282,18 @@ func TestInMemoryStore(t *testing.T) {
 	t.Run("✅ should print 0 when there are no expenses for a specific month of the current year", func(t *testing.T) {
 		// Given
 		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
-		amount := 20
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
 
-		amount = 15
+		amount = 1500
 		description = "Dinner"
 		expense = models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
 
-		amount = 50
+		amount = 5000
 		description = "Dinner"
 		expense = models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
@@ -315,23 +315,23 @@ func TestInMemoryStore(t *testing.T) {
 	t.Run("✅ should print the expenses from a specific month and current year taking on account the updated at", func(t *testing.T) {
 		// Given
 		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
-		amount := 20
+		amount := 2000
 		description := "Lunch"
 
 		expense := models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
 
-		amount = 15
+		amount = 1500
 		description = "Dinner"
 		expense = models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
 
-		amount = 50
+		amount = 5000
 		description = "Dinner"
 		expense = models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
 
-		amount = 100
+		amount = 10000
 		description = "Lunch"
 		expense = models.Expense{Amount: amount, Description: description}
 		store.Add(expense)
@@ -350,6 +350,7 @@ func TestInMemoryStore(t *testing.T) {
 
 		updateAt3 := time.Date(time.Now().Year()+1, time.January, 1, 0, 0, 0, 0, time.UTC)
 		expenses[3].UpdatedAt = &updateAt3
+		store.Reindex()
 
 		// When
 		result := dsl.OutputToString(func() {
@@ -359,4 +360,269 @@ func TestInMemoryStore(t *testing.T) {
 		// Then
 		asserts.Equal("Total expenses: 20\n", result)
 	})
+
+	t.Run("✅ should list expenses sorted by amount in descending order", func(t *testing.T) {
+		// Given
+		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 50, Description: "Dinner"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+
+		// When
+		expenses := store.Find(models.Query{SortBy: models.SortByAmount, Desc: true})
+
+		// Then
+		asserts.Equal(3, len(expenses))
+		asserts.Equal(2, expenses[0].Id)
+		asserts.Equal(1, expenses[1].Id)
+		asserts.Equal(3, expenses[2].Id)
+		asserts.Equal(1, (*store.Expenses)[0].Id)
+	})
+
+	t.Run("✅ should paginate expenses with limit and offset", func(t *testing.T) {
+		// Given
+		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
+		for i := 1; i <= 5; i++ {
+			store.Add(models.Expense{Amount: i, Description: "Lunch"})
+		}
+
+		// When
+		expenses := store.Find(models.Query{Limit: 2, Offset: 2})
+
+		// Then
+		asserts.Equal(2, len(expenses))
+		asserts.Equal(3, expenses[0].Id)
+		asserts.Equal(4, expenses[1].Id)
+		asserts.Equal(0, len(store.Find(models.Query{Offset: 10})))
+	})
+
+	t.Run("✅ should list only the last expenses with tail", func(t *testing.T) {
+		// Given
+		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
+		for i := 1; i <= 5; i++ {
+			store.Add(models.Expense{Amount: i, Description: "Lunch"})
+		}
+		expenses := *store.Expenses
+		expectedMessage := models.HeaderFormat + "\n" + dsl.JoinMessage(expenses[3:4]) + dsl.JoinMessage(expenses[4:5])
+
+		// When
+		result := dsl.OutputToString(func() {
+			store.ListBy(models.Query{Tail: 2})
+		})
+
+		// Then
+		asserts.Equal(expectedMessage, result)
+	})
+
+	t.Run("✅ should print the summary grouped by month with the delta against the previous month", func(t *testing.T) {
+		// Given
+		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
+		store.Add(models.Expense{Amount: 2000, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 1000, Description: "Dinner"})
+		store.Add(models.Expense{Amount: 1500, Description: "Dinner"})
+
+		expenses := *store.Expenses
+		expenses[0].CreatedAt = time.Date(2024, time.July, 3, 0, 0, 0, 0, time.UTC)
+		expenses[1].CreatedAt = time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)
+		expenses[2].CreatedAt = time.Date(2024, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+		expectedMessage := models.SummaryHeaderFormat + "\n" +
+			fmt.Sprintf(models.SummaryStringFormat, "2024-07", 1, "20", "20.00", "", "0", "-20") +
+			fmt.Sprintf(models.SummaryStringFormat, "2024-08", 2, "25", "12.50", "+5", "0", "-25")
+
+		// When
+		result := dsl.OutputToString(func() {
+			store.SummaryGroupedBy(models.GroupByMonth)
+		})
+
+		// Then
+		asserts.Equal(expectedMessage, result)
+	})
+
+	t.Run("✅ should group the summary by weekday in week order", func(t *testing.T) {
+		// Given
+		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 10, Description: "Dinner"})
+
+		expenses := *store.Expenses
+		expenses[0].CreatedAt = time.Date(2024, time.August, 6, 0, 0, 0, 0, time.UTC)
+		expenses[1].CreatedAt = time.Date(2024, time.August, 5, 0, 0, 0, 0, time.UTC)
+
+		// When
+		summaries := expenses.GroupBy(models.GroupByWeekday)
+
+		// Then
+		asserts.Equal(2, len(summaries))
+		asserts.Equal("Monday", summaries[0].Period)
+		asserts.Equal("Tuesday", summaries[1].Period)
+		asserts.Equal(10, summaries[1].Delta)
+	})
+
+	t.Run("✅ should find the expenses of a category within a date range", func(t *testing.T) {
+		// Given
+		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch", Category: "food"})
+		store.Add(models.Expense{Amount: 10, Description: "Bus", Category: "transport"})
+		store.Add(models.Expense{Amount: 15, Description: "Dinner", Category: "food"})
+
+		expenses := *store.Expenses
+		expenses[0].CreatedAt = time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)
+		expenses[1].CreatedAt = time.Date(2024, time.August, 2, 0, 0, 0, 0, time.UTC)
+		expenses[2].CreatedAt = time.Date(2024, time.September, 1, 0, 0, 0, 0, time.UTC)
+
+		// When
+		result := store.Find(models.Query{Category: "Food"}.ForMonth(time.August, 2024))
+
+		// Then
+		asserts.Equal(1, len(result))
+		asserts.Equal(1, result[0].Id)
+	})
+
+	t.Run("✅ should print the income, expenses and net balance when there is income", func(t *testing.T) {
+		// Given
+		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
+		store.Add(models.Expense{Amount: 2000, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 300000, Description: "Salary", Kind: models.KindIncome})
+		store.Add(models.Expense{Amount: 50000, Description: "Savings", Kind: models.KindTransfer})
+
+		// When
+		result := dsl.OutputToString(store.Summary)
+
+		// Then
+		asserts.Equal("Total income: 3000\nTotal expenses: 20\nNet balance: 2980\n", result)
+	})
+
+	t.Run("❌ should not add an income with a negative amount", func(t *testing.T) {
+		// Given
+		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
+
+		// When
+		err := store.Add(models.Expense{Amount: -3000, Description: "Salary", Kind: models.KindIncome})
+
+		// Then
+		asserts.Equal("income amount cannot be negative", err.Error())
+		asserts.Equal(0, len(*store.Expenses))
+	})
+
+	t.Run("✅ should account a refund in the month it was refunded", func(t *testing.T) {
+		// Given
+		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
+		store.Add(models.Expense{Amount: 4000, Description: "Order"})
+		err := store.Add(models.Expense{Amount: 1500, Description: "Refund", Kind: models.KindRefund, RefundOf: 1})
+
+		expenses := *store.Expenses
+		expenses[0].CreatedAt = time.Date(time.Now().Year(), time.January, 10, 0, 0, 0, 0, time.UTC)
+		expenses[1].CreatedAt = time.Date(time.Now().Year(), time.February, 3, 0, 0, 0, 0, time.UTC)
+
+		// When
+		january := dsl.OutputToString(func() {
+			store.SummaryForMonth(time.January)
+		})
+		february := dsl.OutputToString(func() {
+			store.SummaryForMonth(time.February)
+		})
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal("Total expenses: 40\n", january)
+		asserts.Equal("Total expenses: -15\n", february)
+		asserts.Equal(2500, expenses[0].Net(expenses.Refunded()))
+	})
+
+	t.Run("❌ should not refund more than what is left of an expense", func(t *testing.T) {
+		// Given
+		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
+		store.Add(models.Expense{Amount: 4000, Description: "Order"})
+		store.Add(models.Expense{Amount: 2950, Description: "Refund", Kind: models.KindRefund, RefundOf: 1})
+
+		// When
+		err := store.Add(models.Expense{Amount: 2000, Description: "Refund", Kind: models.KindRefund, RefundOf: 1})
+
+		// Then
+		asserts.Equal("refund exceeds the 10.50 left to refund of expense 1", err.Error())
+		asserts.Equal(2, len(*store.Expenses))
+	})
+
+	t.Run("✅ should move a deleted expense to the trash", func(t *testing.T) {
+		// Given
+		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+
+		// When
+		err := store.Delete(1)
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(1, len(store.Find(models.Query{})))
+		trashed := store.Trashed()
+		asserts.Equal(1, len(trashed))
+		asserts.Equal("Lunch", trashed[0].Description)
+		asserts.NotNil(trashed[0].DeletedAt)
+	})
+
+	t.Run("✅ should not reuse the ID of a trashed expense", func(t *testing.T) {
+		// Given
+		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Delete(1)
+
+		// When
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+
+		// Then
+		asserts.Equal(2, store.Find(models.Query{})[0].Id)
+		asserts.Equal(2, store.LastId())
+	})
+
+	t.Run("✅ should restore a trashed expense", func(t *testing.T) {
+		// Given
+		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Delete(1)
+
+		// When
+		err := store.Restore(1)
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(0, len(store.Trashed()))
+		expense, err := store.Get(1)
+		asserts.Nil(err)
+		asserts.Nil(expense.DeletedAt)
+	})
+
+	t.Run("❌ should not restore an expense that is not in the trash", func(t *testing.T) {
+		// Given
+		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+
+		// When
+		err := store.Restore(1)
+
+		// Then
+		asserts.EqualError(err, "expense with ID 1 not found in trash")
+	})
+
+	t.Run("✅ should empty only the expenses trashed before a date", func(t *testing.T) {
+		// Given
+		store := stores.NewInMemoryStore().(*stores.InMemoryStore)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+		store.Delete(1)
+		store.Delete(2)
+		longAgo := time.Now().AddDate(0, 0, -40)
+		store.Trashed()[0].DeletedAt = &longAgo
+
+		// When
+		emptied, err := store.EmptyTrash(time.Now().AddDate(0, 0, -30))
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(1, emptied)
+		trashed := store.Trashed()
+		asserts.Equal(1, len(trashed))
+		asserts.Equal("Coffee", trashed[0].Description)
+	})
 }
@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"expense-tracker/stores"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndex(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("✅ should find an expense by its new date once updated", func(t *testing.T) {
+		// Given
+		store := newIndexedStore(10)
+		from := time.Now().Add(-time.Minute)
+
+		// When
+		err := store.Update(models.Expense{Id: 3, Amount: 25, Description: "Brunch"})
+
+		// Then
+		asserts.Nil(err)
+		expenses := store.Find(models.Query{From: from})
+		asserts.Equal(1, len(expenses))
+		asserts.Equal(3, expenses[0].Id)
+		asserts.Equal(9, len(store.Find(models.Query{To: from})))
+	})
+
+	t.Run("✅ should drop a deleted expense and bring it back restored", func(t *testing.T) {
+		// Given
+		store := newIndexedStore(10)
+		query := models.Query{From: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC)}
+
+		// When
+		store.Delete(2)
+
+		// Then
+		asserts.Equal(2, len(store.Find(query)))
+		_, err := store.Get(2)
+		asserts.NotNil(err)
+		asserts.Nil(store.Restore(2))
+		asserts.Equal(3, len(store.Find(query)))
+	})
+
+	t.Run("✅ should give IDs after the trashed ones", func(t *testing.T) {
+		// Given
+		store := newIndexedStore(3)
+		store.Delete(3)
+
+		// When
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+
+		// Then
+		expense, err := store.Get(4)
+		asserts.Nil(err)
+		asserts.Equal("Coffee", expense.Description)
+	})
+}
+
+func BenchmarkIndex(b *testing.B) {
+	const rows = 100_000
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	query := models.Query{From: from, To: from.AddDate(0, 0, 7)}
+
+	// every sub-benchmark starts from a store of its own, as updates and
+	// deletes change it
+	b.Run(fmt.Sprintf("get/indexed/%d", rows), func(b *testing.B) {
+		store := newIndexedStore(rows)
+		b.ResetTimer()
+		for i := range b.N {
+			store.Get(i*7919%rows + 1)
+		}
+	})
+	b.Run(fmt.Sprintf("get/scan/%d", rows), func(b *testing.B) {
+		expenses := *newIndexedStore(rows).Expenses
+		b.ResetTimer()
+		for i := range b.N {
+			id := i*7919%rows + 1
+			for _, expense := range expenses {
+				if expense.Id == id {
+					break
+				}
+			}
+		}
+	})
+	b.Run(fmt.Sprintf("update/indexed/%d", rows), func(b *testing.B) {
+		store := newIndexedStore(rows)
+		b.ResetTimer()
+		for i := range b.N {
+			store.Update(models.Expense{Id: i%rows + 1, Amount: 20, Description: "Lunch"})
+		}
+	})
+	b.Run(fmt.Sprintf("delete/indexed/%d", rows), func(b *testing.B) {
+		store := newIndexedStore(rows)
+		b.ResetTimer()
+		for i := range b.N {
+			if i > 0 && i%rows == 0 {
+				b.StopTimer()
+				store = newIndexedStore(rows)
+				b.StartTimer()
+			}
+			store.Delete(i*7919%rows + 1)
+		}
+	})
+	b.Run(fmt.Sprintf("delete/scan/%d", rows), func(b *testing.B) {
+		expenses := *newIndexedStore(rows).Expenses
+		b.ResetTimer()
+		for i := range b.N {
+			if i > 0 && i%rows == 0 {
+				b.StopTimer()
+				expenses = *newIndexedStore(rows).Expenses
+				b.StartTimer()
+			}
+			expenses.Take(i*7919%rows + 1)
+		}
+	})
+	b.Run(fmt.Sprintf("range/indexed/%d", rows), func(b *testing.B) {
+		store := newIndexedStore(rows)
+		b.ResetTimer()
+		for range b.N {
+			store.Find(query)
+		}
+	})
+	b.Run(fmt.Sprintf("range/scan/%d", rows), func(b *testing.B) {
+		expenses := *newIndexedStore(rows).Expenses
+		b.ResetTimer()
+		for range b.N {
+			expenses.Find(query)
+		}
+	})
+}
+
+// newIndexedStore adds expenses a few hours apart from the start of 2024.
+func newIndexedStore(rows int) *stores.InMemoryStore {
+	store := stores.NewInMemoryStore().(*stores.InMemoryStore)
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := range rows {
+		store.Add(models.Expense{Amount: i%100 + 1, Description: "Lunch"})
+		(*store.Expenses)[i].CreatedAt = start.Add(time.Duration(i) * 8 * time.Hour)
+	}
+	store.Reindex()
+	return store
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"expense-tracker/stores"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournaledStore(t *testing.T) {
+	asserts := assert.New(t)
+
+	newJournaledStore := func(t *testing.T) (models.Store, models.Journal) {
+		store := stores.NewJournaledStore(stores.NewInMemoryStore(), filepath.Join(t.TempDir(), "journal.csv"))
+		return store, store.(models.Journal)
+	}
+
+	t.Run("✅ should undo a delete", func(t *testing.T) {
+		// Given
+		store, journal := newJournaledStore(t)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Delete(1)
+
+		// When
+		undone, err := journal.Undo(1)
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(models.ActionDelete, undone[0].Action)
+		asserts.Equal(1, len(store.Find(models.Query{})))
+		asserts.Equal(0, len(store.Trashed()))
+	})
+
+	t.Run("✅ should undo an add", func(t *testing.T) {
+		// Given
+		store, journal := newJournaledStore(t)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+
+		// When
+		_, err := journal.Undo(1)
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(0, len(store.Find(models.Query{})))
+	})
+
+	t.Run("✅ should undo and redo an update", func(t *testing.T) {
+		// Given
+		store, journal := newJournaledStore(t)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Update(models.Expense{Id: 1, Amount: 25, Description: "Dinner"})
+
+		// When
+		_, undoErr := journal.Undo(1)
+		undone, _ := store.Get(1)
+		undoneDescription := undone.Description
+		_, redoErr := journal.Redo(1)
+		redone, _ := store.Get(1)
+
+		// Then
+		asserts.Nil(undoErr)
+		asserts.Nil(redoErr)
+		asserts.Equal("Lunch", undoneDescription)
+		asserts.Equal("Dinner", redone.Description)
+		asserts.Equal(25, redone.Amount)
+	})
+
+	t.Run("✅ should undo the last changes latest first", func(t *testing.T) {
+		// Given
+		store, journal := newJournaledStore(t)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+		store.Delete(1)
+
+		// When
+		undone, err := journal.Undo(2)
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(2, len(undone))
+		asserts.Equal(models.ActionDelete, undone[0].Action)
+		asserts.Equal(models.ActionAdd, undone[1].Action)
+		asserts.Equal(2, undone[1].ExpenseId)
+		expenses := store.Find(models.Query{})
+		asserts.Equal(1, len(expenses))
+		asserts.Equal("Lunch", expenses[0].Description)
+	})
+
+	t.Run("❌ should not redo after a new change", func(t *testing.T) {
+		// Given
+		store, journal := newJournaledStore(t)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		journal.Undo(1)
+		store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+
+		// When
+		_, err := journal.Redo(1)
+
+		// Then
+		asserts.EqualError(err, "nothing to redo")
+		asserts.Equal(1, len(journal.History()))
+	})
+
+	t.Run("❌ should not undo without changes", func(t *testing.T) {
+		// Given
+		_, journal := newJournaledStore(t)
+
+		// When
+		_, err := journal.Undo(1)
+
+		// Then
+		asserts.EqualError(err, "nothing to undo")
+	})
+
+	t.Run("✅ should undo across runs", func(t *testing.T) {
+		// Given
+		directory := t.TempDir()
+		expensesFile := filepath.Join(directory, "test.csv")
+		journalFile := filepath.Join(directory, "journal.csv")
+		store := stores.NewJournaledStore(stores.NewCsvStore(expensesFile), journalFile)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Update(models.Expense{Id: 1, Amount: 25, Description: "Dinner"})
+		store.Delete(1)
+
+		// When
+		reloaded := stores.NewJournaledStore(stores.NewCsvStore(expensesFile), journalFile)
+		_, err := reloaded.(models.Journal).Undo(2)
+
+		// Then
+		asserts.Nil(err)
+		expense, getErr := reloaded.Get(1)
+		asserts.Nil(getErr)
+		asserts.Equal("Lunch", expense.Description)
+		asserts.Equal(20, expense.Amount)
+	})
+}
@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"expense-tracker/stores"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionedStore(t *testing.T) {
+	asserts := assert.New(t)
+
+	// writeYear writes the ledger of a past year as an older version would
+	// have, without hashes
+	writeYear := func(directory string, year int, rows string) {
+		content := "ID,Description,Amount,Created At,Updated At\n" + rows
+		asserts.Nil(os.WriteFile(filepath.Join(directory, strconv.Itoa(year)+".csv"), []byte(content), 0644))
+	}
+
+	t.Run("✅ should keep the IDs unique across the years", func(t *testing.T) {
+		// Given
+		directory := t.TempDir()
+		writeYear(directory, 2022, "1,Lunch,20,2022-03-01,\n2,Taxi,15,2022-11-20,\n")
+		writeYear(directory, 2023, "3,Dinner,40,2023-06-10,\n")
+		store := stores.NewPartitionedStore(directory)
+
+		// When
+		err := store.Add(models.Expense{Amount: 10, Description: "Coffee"})
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal(4, store.LastId())
+		expense, err := store.Get(4)
+		asserts.Nil(err)
+		asserts.Equal("Coffee", expense.Description)
+		_, err = os.Stat(filepath.Join(directory, strconv.Itoa(time.Now().Year())+".csv"))
+		asserts.Nil(err)
+		asserts.Equal(4, len(stores.NewPartitionedStore(directory).Find(models.Query{})))
+	})
+
+	t.Run("✅ should change the expenses of past years in their own file", func(t *testing.T) {
+		// Given
+		directory := t.TempDir()
+		writeYear(directory, 2022, "1,Lunch,20,2022-03-01,\n")
+		writeYear(directory, 2023, "2,Dinner,40,2023-06-10,\n")
+		store := stores.NewPartitionedStore(directory)
+
+		// When
+		updateErr := store.Update(models.Expense{Id: 1, Amount: 25, Description: "Brunch"})
+		deleteErr := store.Delete(2)
+
+		// Then
+		asserts.Nil(updateErr)
+		asserts.Nil(deleteErr)
+		reloaded := stores.NewPartitionedStore(directory)
+		expense, err := reloaded.Get(1)
+		asserts.Nil(err)
+		asserts.Equal("Brunch", expense.Description)
+		asserts.Equal(2022, expense.CreatedAt.Year())
+		asserts.Equal(1, len(reloaded.Trashed()))
+		asserts.Nil(reloaded.Restore(2))
+		_, err = reloaded.Get(2)
+		asserts.Nil(err)
+		asserts.NotNil(reloaded.Delete(3))
+	})
+
+	t.Run("✅ should only read the years a query needs", func(t *testing.T) {
+		// Given
+		directory := t.TempDir()
+		writeYear(directory, 2022, "1,Lunch,20,2022-03-01,\n")
+		writeYear(directory, 2023, "2,Dinner,40,2023-06-10,\n")
+		stores.NewPartitionedStore(directory)
+		writeYear(directory, 2022, "1,Lunch,not a number,2022-03-01,\n")
+		store := stores.NewPartitionedStore(directory)
+
+		// When
+		expenses := store.Find(models.Query{From: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)})
+
+		// Then
+		asserts.Equal(1, len(expenses))
+		asserts.Equal("Dinner", expenses[0].Description)
+		asserts.Panics(func() { store.Find(models.Query{}) })
+	})
+
+	t.Run("✅ should add through the audit and the journal without reading past years", func(t *testing.T) {
+		// Given
+		directory := t.TempDir()
+		writeYear(directory, 2022, "1,Lunch,20,2022-03-01,\n")
+		stores.NewPartitionedStore(directory)
+		writeYear(directory, 2022, "1,Lunch,not a number,2022-03-01,\n")
+		partitioned := stores.NewPartitionedStore(directory, stores.WithBackups(stores.BackupPolicy{Count: 10}))
+		audit := stores.NewCsvAuditStore(filepath.Join(directory, "audit.csv"))
+		store := stores.NewJournaledStore(stores.NewAuditedStore(partitioned, audit, "alice"), filepath.Join(directory, "journal.csv"))
+
+		// When
+		err := store.Add(models.Expense{Amount: 1000, Description: "Coffee"})
+
+		// Then
+		asserts.Nil(err)
+		asserts.Equal("Coffee", audit.Revisions(2)[0].Expense.Description)
+		_, statErr := os.Stat(filepath.Join(directory, "backups"))
+		asserts.True(os.IsNotExist(statErr))
+	})
+
+	t.Run("❌ should not refund more than the expense of a past year", func(t *testing.T) {
+		// Given
+		directory := t.TempDir()
+		writeYear(directory, 2022, "1,Lunch,20,2022-03-01,\n")
+		store := stores.NewPartitionedStore(directory)
+
+		// When
+		err := store.Add(models.Expense{Amount: 3000, Description: "Refund", Kind: models.KindRefund, RefundOf: 1})
+
+		// Then
+		asserts.NotNil(err)
+		asserts.Nil(store.Add(models.Expense{Amount: 2000, Description: "Refund", Kind: models.KindRefund, RefundOf: 1}))
+	})
+
+	t.Run("✅ should never give the ID of a purged expense again", func(t *testing.T) {
+		// Given
+		directory := t.TempDir()
+		store := stores.NewPartitionedStore(directory)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Delete(1)
+		store.EmptyTrash(time.Now().Add(time.Hour))
+
+		// When
+		reloaded := stores.NewPartitionedStore(directory)
+		err := reloaded.Add(models.Expense{Amount: 5, Description: "Bus"})
+
+		// Then
+		asserts.Nil(err)
+		expense, err := reloaded.Get(2)
+		asserts.Nil(err)
+		asserts.Equal("Bus", expense.Description)
+	})
+}
@@ -0,0 +1,202 @@
+package tests
+
+import (
+	"expense-tracker/models"
+	"expense-tracker/stores"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamingCsvStore(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("✅ should find what the csv store finds", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		ledger := stores.NewCsvStore(filename)
+		ledger.Add(models.Expense{Amount: 20, Description: "Lunch", Category: "food"})
+		ledger.Add(models.Expense{Amount: 30, Description: "Taxi", Category: "travel"})
+		ledger.Add(models.Expense{Amount: 15, Description: "Dinner", Category: "food"})
+		ledger.Add(models.Expense{Amount: 5, Description: "Refund", Kind: models.KindRefund, RefundOf: 1})
+		ledger.Delete(3)
+
+		// When
+		store := stores.NewStreamingCsvStore(filename)
+
+		// Then
+		ledger = stores.NewCsvStore(filename)
+		query := models.Query{Category: "food"}
+		asserts.Equal(ledger.Find(query), store.Find(query))
+		asserts.Equal(ledger.Trashed(), store.Trashed())
+		expense, err := store.Get(2)
+		asserts.Nil(err)
+		asserts.Equal("Taxi", expense.Description)
+		_, err = store.Get(3)
+		asserts.NotNil(err)
+	})
+
+	t.Run("✅ should total the cash flow in a single pass", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		store := stores.NewStreamingCsvStore(filename).(*stores.StreamingCsvStore)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+		store.Add(models.Expense{Amount: 100, Description: "Salary", Kind: models.KindIncome})
+		store.Add(models.Expense{Amount: 5, Description: "Refund", Kind: models.KindRefund, RefundOf: 1})
+		store.Add(models.Expense{Amount: 30, Description: "Taxi"})
+		store.Delete(4)
+
+		// When
+		cashFlow := store.CashFlow(func(*models.Expense) bool { return true })
+
+		// Then
+		asserts.Equal(models.CashFlow{Spending: 15, Income: 100, HasIncome: true}, cashFlow)
+	})
+
+	t.Run("✅ should stop scanning at the first expense found", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		ledger := stores.NewCsvStore(filename)
+		ledger.Add(models.Expense{Amount: 20, Description: "Lunch", Currency: "EUR"})
+		ledger.Add(models.Expense{Amount: 30, Description: "Taxi"})
+		ledger.Add(models.Expense{Amount: 15, Description: "Dinner", Currency: "EUR"})
+		store := stores.NewStreamingCsvStore(filename).(*stores.StreamingCsvStore)
+
+		// When
+		visited := 0
+		found := store.Any(models.Query{}, func(expense *models.Expense) bool {
+			visited++
+			return expense.Currency == "EUR"
+		})
+		missing := store.Any(models.Query{}, func(expense *models.Expense) bool { return expense.Currency == "GBP" })
+
+		// Then
+		asserts.True(found)
+		asserts.Equal(1, visited)
+		asserts.False(missing)
+	})
+
+	t.Run("✅ should read the changes made through it", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		store := stores.NewStreamingCsvStore(filename)
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+
+		// When
+		err := store.Update(models.Expense{Id: 1, Amount: 25, Description: "Brunch"})
+
+		// Then
+		asserts.Nil(err)
+		expenses := store.Find(models.Query{})
+		asserts.Equal(1, len(expenses))
+		asserts.Equal("Brunch", expenses[0].Description)
+	})
+
+	t.Run("✅ should verify and back up the ledger it streams", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		store := stores.NewStreamingCsvStore(filename, stores.WithBackups(stores.BackupPolicy{Count: 5}))
+		store.Add(models.Expense{Amount: 20, Description: "Lunch"})
+
+		// When
+		verifier, verifiable := models.As[models.Verifier](store)
+		backuper, backedUp := models.As[models.Backuper](store)
+
+		// Then
+		asserts.True(verifiable)
+		verified, err := verifier.Verify()
+		asserts.Nil(err)
+		asserts.Equal(1, verified)
+		asserts.Equal(stores.NewCsvStore(filename).(models.Verifier).Head(), verifier.Head())
+		asserts.True(backedUp)
+		_, err = backuper.CreateBackup()
+		asserts.Nil(err)
+	})
+
+	t.Run("✅ should stream an encrypted ledger", func(t *testing.T) {
+		// Given
+		filename := filepath.Join(t.TempDir(), "test.csv")
+		cipher, err := stores.NewKeyCipher([]byte(strings.Repeat("k", 32)))
+		asserts.Nil(err)
+		stores.NewCsvStore(filename, stores.WithCodec(cipher)).Add(models.Expense{Amount: 20, Description: "Lunch"})
+
+		// When
+		expenses := stores.NewStreamingCsvStore(filename, stores.WithCodec(cipher)).Find(models.Query{})
+
+		// Then
+		asserts.Equal(1, len(expenses))
+		asserts.Equal("Lunch", expenses[0].Description)
+	})
+}
+
+// BenchmarkSummary reports the heap still held once a summary is printed,
+// which grows with the ledger for the csv store and stays flat when
+// streaming.
+func BenchmarkSummary(b *testing.B) {
+	constructors := map[string]func(filename string) models.Store{
+		"csv":       func(filename string) models.Store { return stores.NewCsvStore(filename) },
+		"streaming": func(filename string) models.Store { return stores.NewStreamingCsvStore(filename) },
+	}
+
+	for _, rows := range []int{1_000, 10_000, 100_000} {
+		filename := writeLedger(b, rows)
+		for _, name := range []string{"csv", "streaming"} {
+			b.Run(fmt.Sprintf("%s/%d", name, rows), func(b *testing.B) {
+				silenceOutput(b)
+				b.ReportAllocs()
+				var retained uint64
+				for range b.N {
+					before := heapAlloc()
+					store := constructors[name](filename)
+					store.SummaryForMonth(time.March)
+					retained = max(heapAlloc(), before) - before
+					runtime.KeepAlive(store)
+				}
+				b.ReportMetric(float64(retained), "retained-B")
+			})
+		}
+	}
+}
+
+// writeLedger writes a ledger of rows spread over a year, as imported.
+func writeLedger(b *testing.B, rows int) string {
+	var content strings.Builder
+	content.WriteString("ID,Description,Amount,Created At,Updated At,Category\n")
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := range rows {
+		createdAt := start.AddDate(0, 0, i%365).Format(models.DateFormat)
+		fmt.Fprintf(&content, "%d,Expense %d,%d,%s,,food\n", i+1, i+1, i%100+1, createdAt)
+	}
+
+	filename := filepath.Join(b.TempDir(), "test.csv")
+	if err := os.WriteFile(filename, []byte(content.String()), 0644); err != nil {
+		b.Fatal(err)
+	}
+	return filename
+}
+
+func heapAlloc() uint64 {
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+func silenceOutput(b *testing.B) {
+	stdout := os.Stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	os.Stdout = devNull
+	b.Cleanup(func() {
+		os.Stdout = stdout
+		devNull.Close()
+	})
+}